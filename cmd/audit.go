@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"project/config"
+	"project/internal/repo"
+	"project/internal/service"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func NewAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log",
+	}
+	cmd.AddCommand(newAuditVerifyCommand())
+	return cmd
+}
+
+func newAuditVerifyCommand() *cobra.Command {
+	var batchSize int
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the audit log hash chain",
+		Long: `Walks audit_events in ID order, recomputing each entry's hash and checking
+that it links to the previous entry. Exits non-zero at the first entry that was
+modified, removed or reordered.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSize <= 0 || batchSize > 10000 {
+				return fmt.Errorf("--batch-size must be between 1 and 10000")
+			}
+			var events *repo.GormTransferRepo
+			app := fx.New(
+				fx.NopLogger,
+				fx.Provide(
+					config.LoadConfig,
+					repo.NewPostgresDB,
+					repo.NewPostgresTransferRepo,
+				),
+				fx.Populate(&events),
+			)
+			if err := app.Err(); err != nil {
+				return err
+			}
+
+			res, err := service.VerifyAuditChain(context.Background(), events, batchSize)
+			if err != nil {
+				return fmt.Errorf("audit chain broken after %d valid event(s): %w", res.Checked, err)
+			}
+			log.Printf("[Audit] chain intact: %d event(s), last id=%d hash=%s", res.Checked, res.LastID, res.LastHash)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&batchSize, "batch-size", 1000, "events read per query")
+	return cmd
+}
@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"project/config"
+	pb "project/pkg/pb"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func NewClientCommand() *cobra.Command {
+	opts := &clientOptions{}
+	cmd := &cobra.Command{
+		Use:   "client",
+		Short: "Call the running gRPC server",
+		Long: `Talks to the gRPC server with the generated clients. "login" stores the access
+token in a local file (mode 0600) that the other commands send until it expires.
+
+Defaults come from CLIENT_GRPC_ADDR, CLIENT_GRPC_TLS, CLIENT_GRPC_CA_FILE,
+CLIENT_GRPC_CERT_FILE, CLIENT_GRPC_KEY_FILE, CLIENT_GRPC_SERVER_NAME and
+CLIENT_TOKEN_FILE.`,
+	}
+	opts.bind(cmd)
+	cmd.AddCommand(
+		newClientLoginCommand(opts),
+		newClientLogoutCommand(opts),
+		newClientBalanceCommand(opts),
+		newClientSendCommand(opts),
+		newClientHistoryCommand(opts),
+	)
+	return cmd
+}
+
+type clientOptions struct {
+	addr      string
+	tokenFile string
+	output    string
+	timeout   time.Duration
+}
+
+func (o *clientOptions) bind(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.StringVar(&o.addr, "addr", "", "gRPC server address (default $CLIENT_GRPC_ADDR or localhost:9090)")
+	f.StringVar(&o.tokenFile, "token-file", "", "where the access token is cached (default $CLIENT_TOKEN_FILE or <user config dir>/myapp/token.json)")
+	f.StringVarP(&o.output, "output", "o", "table", "output format: table or json")
+	f.DurationVar(&o.timeout, "timeout", 10*time.Second, "per-request timeout")
+}
+
+// session is an open connection plus, for authenticated calls, the cached
+// token attached to every request.
+type session struct {
+	conn      *grpc.ClientConn
+	cfg       config.ClientConfig
+	tokenPath string
+	token     *cachedToken
+	opts      *clientOptions
+}
+
+func (o *clientOptions) open(authenticated bool) (*session, error) {
+	if o.output != "table" && o.output != "json" {
+		return nil, fmt.Errorf("--output must be table or json")
+	}
+	cfg := config.LoadConfig().Client
+	if o.addr != "" {
+		cfg.GRPCAddr = o.addr
+	}
+	path, err := o.resolveTokenFile(cfg.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{cfg: cfg, tokenPath: path, opts: o}
+	if authenticated {
+		if s.token, err = loadToken(path); err != nil {
+			return nil, err
+		}
+		if s.token.Addr != cfg.GRPCAddr {
+			return nil, fmt.Errorf("cached token is for %s, not %s; run `client login`", s.token.Addr, cfg.GRPCAddr)
+		}
+		if s.token.expired(time.Now()) {
+			return nil, fmt.Errorf("session expired at %s; run `client login`", s.token.ExpiresAt.Local().Format(time.RFC3339))
+		}
+	}
+
+	creds, _, err := grpcClientCredentials(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if s.conn, err = grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(creds)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (o *clientOptions) resolveTokenFile(configured string) (string, error) {
+	switch {
+	case o.tokenFile != "":
+		return o.tokenFile, nil
+	case configured != "":
+		return configured, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate token file: %w; set --token-file", err)
+	}
+	return filepath.Join(dir, "myapp", "token.json"), nil
+}
+
+func (s *session) Close() error {
+	return s.conn.Close()
+}
+
+// context returns a request context with the timeout applied and, when
+// logged in, the bearer token attached.
+func (s *session) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.timeout)
+	if s.token != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.token.AccessToken)
+	}
+	return ctx, cancel
+}
+
+// print writes msg as protojson, or calls table to render it.
+func (s *session) print(w io.Writer, msg proto.Message, table func(w *tabwriter.Writer)) error {
+	if s.opts.output == "json" {
+		data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// cachedToken is the on-disk form of a login.
+type cachedToken struct {
+	Addr        string    `json:"addr"`
+	UserID      int64     `json:"user_id"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (t *cachedToken) expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+func loadToken(path string) (*cachedToken, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("not logged in; run `client login`")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t cachedToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+func saveToken(path string, t *cachedToken) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so a concurrent reader never sees half a file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// tokenExpiry reads the exp claim without verifying the signature; the
+// server does that. A token without one is treated as non-expiring.
+func tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decode access token: %w", err)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("decode access token: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// rpcError turns a gRPC status into a one-line CLI error, including the
+// machine-readable reason when the server sent one.
+func rpcError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason != "" {
+			return fmt.Errorf("%s (%s): %s", st.Code(), info.Reason, st.Message())
+		}
+	}
+	return fmt.Errorf("%s: %s", st.Code(), st.Message())
+}
+
+func newClientLoginCommand(opts *clientOptions) *cobra.Command {
+	var (
+		userID   int64
+		password passwordFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and cache the access token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pw, err := password.read(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			s, err := opts.open(false)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			resp, err := pb.NewAuthServiceClient(s.conn).Login(ctx, &pb.LoginRequest{Username: userID, Password: pw})
+			if err != nil {
+				return rpcError(err)
+			}
+			exp, err := tokenExpiry(resp.AccessToken)
+			if err != nil {
+				return err
+			}
+			t := &cachedToken{Addr: s.cfg.GRPCAddr, UserID: userID, AccessToken: resp.AccessToken, ExpiresAt: exp}
+			if err := saveToken(s.tokenPath, t); err != nil {
+				return err
+			}
+			if exp.IsZero() {
+				fmt.Fprintf(cmd.OutOrStdout(), "logged in as user %d\n", userID)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "logged in as user %d until %s\n", userID, exp.Local().Format(time.RFC3339))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&userID, "user", 0, "user ID")
+	password.bind(cmd)
+	_ = cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+func newClientLogoutCommand(opts *clientOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke the cached token and delete it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.open(true)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			_, err = pb.NewAuthServiceClient(s.conn).Logout(ctx, &pb.LogoutRequest{})
+			// A token the server no longer accepts is as good as revoked.
+			if err != nil && status.Code(err) != codes.Unauthenticated {
+				return rpcError(err)
+			}
+			if err := os.Remove(s.tokenPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "logged out")
+			return nil
+		},
+	}
+}
+
+func newClientBalanceCommand(opts *clientOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "balance",
+		Short: "Show your balance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.open(true)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			resp, err := pb.NewTransferServiceClient(s.conn).GetBalance(ctx, &pb.GetBalanceRequest{})
+			if err != nil {
+				return rpcError(err)
+			}
+			return s.print(cmd.OutOrStdout(), resp, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "USER\tBALANCE")
+				fmt.Fprintf(w, "%d\t%d\n", resp.UserId, resp.Balance)
+			})
+		},
+	}
+}
+
+func newClientSendCommand(opts *clientOptions) *cobra.Command {
+	var to, amount int64
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send money to another user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.open(true)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			resp, err := pb.NewTransferServiceClient(s.conn).SendMoney(ctx, &pb.SendMoneyRequest{To: to, Amount: amount})
+			if err != nil {
+				return rpcError(err)
+			}
+			return s.print(cmd.OutOrStdout(), resp, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "TRANSACTION\tTO\tAMOUNT")
+				fmt.Fprintf(w, "%d\t%d\t%d\n", resp.TransactionId, to, amount)
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&to, "to", 0, "recipient user ID")
+	cmd.Flags().Int64Var(&amount, "amount", 0, "amount to send")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("amount")
+	return cmd
+}
+
+// historyFilter narrows the transactions returned by the server. The API
+// has no server-side filters, so they are applied here.
+type historyFilter struct {
+	to        int64
+	minAmount int64
+	maxAmount int64
+	limit     int
+}
+
+func (f historyFilter) apply(txs []*pb.Transaction) []*pb.Transaction {
+	var out []*pb.Transaction
+	for _, tx := range txs {
+		if f.limit > 0 && len(out) == f.limit {
+			break
+		}
+		if f.to != 0 && tx.To != f.to {
+			continue
+		}
+		if f.minAmount != 0 && tx.Amount < f.minAmount {
+			continue
+		}
+		if f.maxAmount != 0 && tx.Amount > f.maxAmount {
+			continue
+		}
+		out = append(out, tx)
+	}
+	return out
+}
+
+func newClientHistoryCommand(opts *clientOptions) *cobra.Command {
+	var f historyFilter
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List transfers you have sent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+			s, err := opts.open(true)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			resp, err := pb.NewTransferServiceClient(s.conn).ListTransactions(ctx, &pb.ListTransactionsRequest{})
+			if err != nil {
+				return rpcError(err)
+			}
+			txs := f.apply(resp.Transactions)
+			out := &pb.ListTransactionsResponse{Number: int64(len(txs)), Transactions: txs}
+			return s.print(cmd.OutOrStdout(), out, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\tFROM\tTO\tAMOUNT")
+				for _, tx := range txs {
+					fmt.Fprintf(w, "%d\t%d\t%d\t%d\n", tx.Id, tx.From, tx.To, tx.Amount)
+				}
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&f.to, "to", 0, "only transfers to this user")
+	cmd.Flags().Int64Var(&f.minAmount, "min-amount", 0, "only transfers of at least this amount")
+	cmd.Flags().Int64Var(&f.maxAmount, "max-amount", 0, "only transfers of at most this amount")
+	cmd.Flags().IntVar(&f.limit, "limit", 0, "show at most this many (0 = all)")
+	return cmd
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "project/pkg/pb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCache_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "token.json")
+	_, err := loadToken(path)
+	require.ErrorContains(t, err, "not logged in")
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"7","exp":1700000000}`))
+	exp, err := tokenExpiry("header." + payload + ".sig")
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1700000000, 0), exp)
+
+	want := &cachedToken{Addr: "localhost:9090", UserID: 7, AccessToken: "tok", ExpiresAt: exp}
+	require.NoError(t, saveToken(path, want))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, err := loadToken(path)
+	require.NoError(t, err)
+	require.Equal(t, want.AccessToken, got.AccessToken)
+	require.True(t, got.expired(exp))
+	require.False(t, got.expired(exp.Add(-time.Second)))
+}
+
+func TestHistoryFilter(t *testing.T) {
+	txs := []*pb.Transaction{
+		{Id: 1, To: 2, Amount: 10},
+		{Id: 2, To: 3, Amount: 50},
+		{Id: 3, To: 2, Amount: 100},
+		{Id: 4, To: 2, Amount: 500},
+	}
+	ids := func(txs []*pb.Transaction) []int64 {
+		var out []int64
+		for _, tx := range txs {
+			out = append(out, tx.Id)
+		}
+		return out
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, ids(historyFilter{}.apply(txs)))
+	require.Equal(t, []int64{1, 3, 4}, ids(historyFilter{to: 2}.apply(txs)))
+	require.Equal(t, []int64{2, 3}, ids(historyFilter{minAmount: 50, maxAmount: 100}.apply(txs)))
+	require.Equal(t, []int64{3}, ids(historyFilter{to: 2, minAmount: 50, limit: 1}.apply(txs)))
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"project/config"
 	"project/internal/repo"
+	"project/internal/service"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
@@ -19,29 +22,54 @@ func NewPubSubConsumerCommand() *cobra.Command {
 				fx.Provide(
 					config.LoadConfig,
 					repo.NewPubSubClient,
+					fx.Annotate(
+						repo.NewRedisClient,
+						fx.As(new(service.EventStore)),
+					),
+					service.NewEventConsumer,
+				),
+				fx.Invoke(
+					RegisterPubSubConsumer,
+					RegisterMetricsLifecycle,
 				),
-				fx.Invoke(RegisterPubSubConsumer),
 			)
 			app.Run()
 		},
 	}
 }
 
-func RegisterPubSubConsumer(lc fx.Lifecycle, ps *repo.PubSub) {
+// RegisterPubSubConsumer pulls events until the app stops. Stopping waits
+// for the batch in hand to be handled and settled.
+func RegisterPubSubConsumer(lc fx.Lifecycle, ps *repo.PubSub, consumer *service.EventConsumer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
 	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
+		OnStart: func(startCtx context.Context) error {
 			fmt.Println("Starting PubSub consumer...")
+			if err := ps.EnsureSubscription(startCtx); err != nil {
+				return err
+			}
 			go func() {
-				if err := ps.Subscribe(ctx); err != nil {
+				defer close(done)
+				handle := func(ctx context.Context, msg *pubsubpb.PubsubMessage) error {
+					return consumer.Handle(ctx, msg.Data)
+				}
+				if err := ps.Subscribe(ctx, handle); err != nil && ctx.Err() == nil {
 					fmt.Printf("PubSub consumer error: %v\n", err)
 				}
 			}()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
+		OnStop: func(stopCtx context.Context) error {
 			fmt.Println("Stopping PubSub consumer...")
-			// ctx cancel sẽ dừng Receive()
-			return nil
+			cancel()
+			select {
+			case <-done:
+				return ps.Close()
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
 		},
 	})
 }
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/service"
+	"project/pkg/interceptor"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// devDemoUsers are the rows of seed.sql. The passwords are password123,
+// password456 and password; alice is also an admin in dev mode.
+var devDemoUsers = []model.User{
+	{ID: 1, Name: "alice", Balance: 10000, Roles: "user admin", Password: "$2a$10$6Uo9VmU.2UTwJ6iXf/3E5eL3zQiQiyN6VvT54StM2Q/Dye09zyGrW"},
+	{ID: 2, Name: "bob", Balance: 5000, Password: "$2a$10$0Bch0Vk5AasCtDE1kYrmg.2K3b0DGfxMFjGxa4WcO77eExKcGtB3O"},
+	{ID: 3, Name: "bb", Balance: 5000, Password: "$2a$10$QOIEuaIEl2KAd0Jfb3iJVeQIUjoFnYPKFA0JLS3cE.pOwUEOLfv9a"},
+}
+
+func NewDevCommand() *cobra.Command {
+	var fixtures model.SeedUsersInput
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Run the gRPC server, gateway and consumer in one process with in-memory storage",
+		Long: `Starts everything "server" and "pubsub-consumer" do, without Postgres, Redis or
+the Pub/Sub emulator: users, transactions, API keys and the audit log live in
+memory, sessions and consumer dedup state in an in-memory token store, and
+events go through an in-process broker. Nothing survives a restart.
+
+The demo users from seed.sql are created on start: alice (1, password123,
+admin), bob (2, password456) and bb (3, password). --fixtures adds generated
+users as "users seed" does.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			app := fx.New(
+				memoryProviders(),
+				apiProviders(),
+				fx.Supply(fixtures),
+				fx.Invoke(
+					seedDevData,
+					RegisterMemoryBrokerLifecycle,
+					RegisterEventRelayLifecycle,
+					RegisterSweeperLifecycle,
+					RegisterHTTPLifecycle,
+					RegisterGRPCLifecycle,
+					RegisterMetricsLifecycle,
+				),
+			)
+			app.Run()
+		},
+	}
+	cmd.Flags().IntVar(&fixtures.Count, "fixtures", 0, "also generate this many users")
+	cmd.Flags().Int64Var(&fixtures.Balance, "fixture-balance", 10000, "opening balance of each generated user")
+	cmd.Flags().IntVar(&fixtures.Transactions, "fixture-transactions", 100, "random transfers between generated users")
+	cmd.Flags().Int64Var(&fixtures.Seed, "fixture-seed", 1, "random seed for generated users and transfers")
+	fixtures.Password = "password123"
+	return cmd
+}
+
+// memoryProviders supplies the config, a JWT signing key that may be
+// ephemeral, and in-memory stand-ins for Postgres, Redis and Pub/Sub, plus the
+// consumer and user services that run on them.
+func memoryProviders() fx.Option {
+	return fx.Provide(
+		config.LoadConfig,
+		newDevSigningKey,
+		fx.Annotate(
+			repo.NewMemoryTransferRepo,
+			fx.As(fx.Self()),
+			fx.As(new(service.TransferRepo)),
+			fx.As(new(service.DBClient)),
+			fx.As(new(service.AdminRepo)),
+			fx.As(new(service.UserRepo)),
+			fx.As(new(interceptor.AccountStatus)),
+			fx.As(new(service.AuditRecorder)),
+			fx.As(new(HotAccountSweeper)),
+			fx.As(new(EventRelay)),
+		),
+		fx.Annotate(
+			repo.NewMemoryAPIKeyRepo,
+			fx.As(new(service.APIKeyRepo)),
+		),
+		fx.Annotate(
+			repo.NewMemoryTokenStore,
+			fx.As(fx.Self()),
+			fx.As(new(service.RedisClient)),
+			fx.As(new(interceptor.RedisToken)),
+			fx.As(new(service.EventStore)),
+		),
+		fx.Annotate(
+			repo.NewMemoryBroker,
+			fx.As(fx.Self()),
+			fx.As(new(EventSender)),
+		),
+		service.NewEventConsumer,
+		service.NewUserService,
+	)
+}
+
+func seedDevData(store *repo.MemoryTransferRepo, users *service.UserService, fixtures model.SeedUsersInput) error {
+	for _, u := range devDemoUsers {
+		if err := store.SeedUser(u); err != nil {
+			return err
+		}
+	}
+	log.Printf("[Dev] seeded demo users alice (1), bob (2) and bb (3)")
+	if fixtures.Count == 0 {
+		return nil
+	}
+	fixtures.ActorID = model.SystemAccountID
+	out, err := users.Seed(context.Background(), fixtures)
+	if err != nil {
+		return err
+	}
+	log.Printf("[Dev] generated users %d-%d (password %s)", out.Users[0].ID, out.Users[len(out.Users)-1].ID, fixtures.Password)
+	return nil
+}
+
+// RegisterMemoryBrokerLifecycle feeds published events to the consumer. It
+// must be invoked before RegisterEventRelayLifecycle and the servers: fx stops
+// hooks in reverse order, so the queue drains after the relay has stopped.
+func RegisterMemoryBrokerLifecycle(lc fx.Lifecycle, broker *repo.MemoryBroker, consumer *service.EventConsumer) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			broker.Start(func(ctx context.Context, msg *pubsubpb.PubsubMessage) error {
+				return consumer.Handle(ctx, msg.Data)
+			})
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Println("draining in-memory events...")
+			return broker.Stop(ctx)
+		},
+	})
+}
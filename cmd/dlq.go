@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func NewDLQCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect, replay and purge dead-lettered Pub/Sub messages",
+	}
+	cmd.AddCommand(
+		newDLQListCommand(),
+		newDLQShowCommand(),
+		newDLQReplayCommand(),
+		newDLQPurgeCommand(),
+	)
+	return cmd
+}
+
+type dlqFilter struct {
+	eventType string
+	since     string
+	until     string
+	limit     int
+}
+
+func (f *dlqFilter) bind(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.eventType, "type", "", "only messages with this event type (e.g. TransferCompleted)")
+	cmd.Flags().StringVar(&f.since, "since", "", "only events that occurred at or after this RFC3339 time")
+	cmd.Flags().StringVar(&f.until, "until", "", "only events that occurred before this RFC3339 time")
+	cmd.Flags().IntVar(&f.limit, "limit", 0, "maximum number of messages to pull (0 = all)")
+}
+
+func (f *dlqFilter) matcher() (func(deadLetterView) bool, error) {
+	var since, until time.Time
+	var err error
+	if f.since != "" {
+		if since, err = time.Parse(time.RFC3339, f.since); err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if f.until != "" {
+		if until, err = time.Parse(time.RFC3339, f.until); err != nil {
+			return nil, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return func(v deadLetterView) bool {
+		if f.eventType != "" && v.event.Type != f.eventType {
+			return false
+		}
+		at := v.occurredAt()
+		if !since.IsZero() && at.Before(since) {
+			return false
+		}
+		if !until.IsZero() && !at.Before(until) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// deadLetterView pairs a dead letter with its decoded event, if it decodes.
+type deadLetterView struct {
+	msg      repo.DeadLetter
+	event    model.Event
+	decodeOK bool
+}
+
+func newDeadLetterView(msg repo.DeadLetter) deadLetterView {
+	event, err := model.DecodeEvent(msg.Data)
+	return deadLetterView{msg: msg, event: event, decodeOK: err == nil}
+}
+
+func (v deadLetterView) occurredAt() time.Time {
+	if v.decodeOK {
+		return v.event.OccurredAt
+	}
+	return v.msg.PublishTime
+}
+
+func (v deadLetterView) summary() string {
+	if !v.decodeOK {
+		return fmt.Sprintf("undecodable payload (%d bytes)", len(v.msg.Data))
+	}
+	switch v.event.Type {
+	case model.EventTransferCompleted:
+		var t model.TransferCompleted
+		if err := json.Unmarshal(v.event.Data, &t); err == nil {
+			return fmt.Sprintf("%d -> %d amount=%d", t.From, t.To, t.Amount)
+		}
+	}
+	return string(v.event.Data)
+}
+
+func (v deadLetterView) eventType() string {
+	if !v.decodeOK {
+		return "-"
+	}
+	return v.event.Type
+}
+
+// deadLetterSource is what the dlq commands need from *repo.PubSub.
+type deadLetterSource interface {
+	PullDeadLetters(ctx context.Context, max int) ([]repo.DeadLetter, error)
+	ExtendDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error
+	AckDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error
+	ReleaseDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error
+	Republish(msg repo.DeadLetter) error
+}
+
+// deadLetterPageFunc handles one page of dead letters. It returns the IDs of
+// the messages it acked, and done to stop paging.
+type deadLetterPageFunc func(ctx context.Context, src deadLetterSource, page []deadLetterView) (acked map[string]bool, done bool, err error)
+
+// deadLetterReleaseTimeout bounds releasing held messages once paging has
+// stopped, including after an interrupt.
+const deadLetterReleaseTimeout = 30 * time.Second
+
+// withDeadLetters connects to Pub/Sub and runs pageDeadLetters. An interrupt
+// stops paging; held messages are still released.
+func withDeadLetters(limit int, fn deadLetterPageFunc) error {
+	var ps *repo.PubSub
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(config.LoadConfig, repo.NewPubSubClient),
+		fx.Populate(&ps),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	defer ps.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return pageDeadLetters(ctx, ps, limit, fn)
+}
+
+// pageDeadLetters pulls dead letters a page at a time and hands each page to
+// fn, until the subscription is drained, limit messages (0 = no limit) have
+// been seen or fn is done. Messages fn does not ack are held: their leases
+// are refreshed before every pull, so later pages do not return them again,
+// and they are released however paging ends, leaving the subscription as it
+// was.
+func pageDeadLetters(ctx context.Context, src deadLetterSource, limit int, fn deadLetterPageFunc) (err error) {
+	held := make(map[string]repo.DeadLetter)
+	acked := make(map[string]bool)
+	heldList := func() []repo.DeadLetter {
+		out := make([]repo.DeadLetter, 0, len(held))
+		for _, m := range held {
+			out = append(out, m)
+		}
+		return out
+	}
+	defer func() {
+		// ctx may be what stopped paging, so release under a fresh deadline.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadLetterReleaseTimeout)
+		defer cancel()
+		if releaseErr := src.ReleaseDeadLetters(releaseCtx, heldList()); releaseErr != nil && err == nil {
+			err = fmt.Errorf("release dead letters: %w", releaseErr)
+		}
+	}()
+
+	for seen := 0; limit <= 0 || seen < limit; {
+		if err = src.ExtendDeadLetters(ctx, heldList()); err != nil {
+			break
+		}
+		max := repo.DeadLetterPageSize
+		if limit > 0 {
+			max = min(max, limit-seen)
+		}
+		var msgs []repo.DeadLetter
+		msgs, err = src.PullDeadLetters(ctx, max)
+
+		// A message can come back if its lease lapsed anyway; only the
+		// newest ack ID is valid, and one already acked is acked again.
+		var page []deadLetterView
+		var reack []repo.DeadLetter
+		for _, m := range msgs {
+			_, again := held[m.MessageID]
+			switch {
+			case acked[m.MessageID]:
+				reack = append(reack, m)
+			case again:
+				held[m.MessageID] = m
+			default:
+				held[m.MessageID] = m
+				page = append(page, newDeadLetterView(m))
+			}
+		}
+		if err != nil {
+			break
+		}
+		if err = src.AckDeadLetters(ctx, reack); err != nil {
+			break
+		}
+		if len(msgs) == 0 {
+			break
+		}
+		if len(page) == 0 {
+			continue
+		}
+		seen += len(page)
+		sort.Slice(page, func(i, j int) bool { return page[i].msg.PublishTime.Before(page[j].msg.PublishTime) })
+
+		var pageAcked map[string]bool
+		var done bool
+		pageAcked, done, err = fn(ctx, src, page)
+		for id := range pageAcked {
+			acked[id] = true
+			delete(held, id)
+		}
+		if err != nil || done {
+			break
+		}
+	}
+	return err
+}
+
+func newDLQListCommand() *cobra.Command {
+	var filter dlqFilter
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List dead-lettered messages with decoded event summaries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			match, err := filter.matcher()
+			if err != nil {
+				return err
+			}
+			var views []deadLetterView
+			err = withDeadLetters(filter.limit, func(ctx context.Context, src deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+				for _, v := range page {
+					if match(v) {
+						views = append(views, v)
+					}
+				}
+				return nil, false, nil
+			})
+			if err != nil {
+				return err
+			}
+			sort.Slice(views, func(i, j int) bool { return views[i].msg.PublishTime.Before(views[j].msg.PublishTime) })
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MESSAGE ID\tPUBLISHED\tATTEMPTS\tTYPE\tEVENT ID\tSUMMARY")
+			for _, v := range views {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					v.msg.MessageID,
+					v.msg.PublishTime.Format(time.RFC3339),
+					orDash(v.msg.Attributes["CloudPubSubDeadLetterSourceDeliveryCount"]),
+					v.eventType(),
+					orDash(v.event.ID),
+					v.summary(),
+				)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			fmt.Printf("%d message(s)\n", len(views))
+			return nil
+		},
+	}
+	filter.bind(cmd)
+	return cmd
+}
+
+func newDLQShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <message-id>",
+		Short: "Show a dead-lettered message in full",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found := false
+			err := withDeadLetters(0, func(ctx context.Context, src deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+				for _, v := range page {
+					if v.msg.MessageID != args[0] {
+						continue
+					}
+					found = true
+					fmt.Printf("Message ID:   %s\n", v.msg.MessageID)
+					fmt.Printf("Published:    %s\n", v.msg.PublishTime.Format(time.RFC3339))
+					fmt.Printf("Event type:   %s\n", v.eventType())
+					fmt.Printf("Event ID:     %s\n", orDash(v.event.ID))
+					if v.decodeOK {
+						fmt.Printf("Occurred at:  %s\n", v.event.OccurredAt.Format(time.RFC3339))
+					}
+					fmt.Println("Attributes:")
+					keys := make([]string, 0, len(v.msg.Attributes))
+					for k := range v.msg.Attributes {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						fmt.Printf("  %s=%s\n", k, v.msg.Attributes[k])
+					}
+					fmt.Println("Data:")
+					var pretty bytes.Buffer
+					if err := json.Indent(&pretty, v.msg.Data, "  ", "  "); err == nil {
+						fmt.Printf("  %s\n", pretty.String())
+					} else {
+						fmt.Printf("  %q\n", v.msg.Data)
+					}
+					return nil, true, nil
+				}
+				return nil, false, nil
+			})
+			if err == nil && !found {
+				err = fmt.Errorf("message %s not found in dead-letter subscription", args[0])
+			}
+			return err
+		},
+	}
+}
+
+func newDLQReplayCommand() *cobra.Command {
+	var filter dlqFilter
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Republish dead-lettered messages to the main topic",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			match, err := filter.matcher()
+			if err != nil {
+				return err
+			}
+			replayed := 0
+			err = withDeadLetters(filter.limit, func(ctx context.Context, src deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+				acked := make(map[string]bool)
+				for _, v := range page {
+					if !match(v) {
+						continue
+					}
+					if dryRun {
+						fmt.Printf("would replay %s (%s) %s\n", v.msg.MessageID, v.eventType(), v.summary())
+						continue
+					}
+					if err := src.Republish(v.msg); err != nil {
+						return acked, false, fmt.Errorf("replay %s: %w", v.msg.MessageID, err)
+					}
+					if err := src.AckDeadLetters(ctx, []repo.DeadLetter{v.msg}); err != nil {
+						return acked, false, fmt.Errorf("ack replayed %s: %w", v.msg.MessageID, err)
+					}
+					acked[v.msg.MessageID] = true
+					replayed++
+					fmt.Printf("replayed %s (%s) %s\n", v.msg.MessageID, v.eventType(), v.summary())
+				}
+				return acked, false, nil
+			})
+			fmt.Printf("%d message(s) replayed\n", replayed)
+			return err
+		},
+	}
+	filter.bind(cmd)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print what would be replayed without publishing")
+	return cmd
+}
+
+func newDLQPurgeCommand() *cobra.Command {
+	var filter dlqFilter
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently drop dead-lettered messages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("refusing to purge without --yes")
+			}
+			match, err := filter.matcher()
+			if err != nil {
+				return err
+			}
+			purged := 0
+			err = withDeadLetters(filter.limit, func(ctx context.Context, src deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+				acked := make(map[string]bool)
+				var purge []repo.DeadLetter
+				for _, v := range page {
+					if match(v) {
+						purge = append(purge, v.msg)
+					}
+				}
+				if err := src.AckDeadLetters(ctx, purge); err != nil {
+					return acked, false, fmt.Errorf("purge: %w", err)
+				}
+				for _, m := range purge {
+					acked[m.MessageID] = true
+				}
+				purged += len(purge)
+				return acked, false, nil
+			})
+			fmt.Printf("%d message(s) purged\n", purged)
+			return err
+		},
+	}
+	filter.bind(cmd)
+	cmd.Flags().BoolVar(&yes, "yes", false, "confirm the purge")
+	return cmd
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"project/internal/repo"
+
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedDeadLetters returns one scripted page per pull and records the
+// ack IDs passed to every other call.
+type scriptedDeadLetters struct {
+	pages    [][]repo.DeadLetter
+	pulls    int
+	extended [][]string
+	acked    []string
+	released []string
+}
+
+func (s *scriptedDeadLetters) PullDeadLetters(ctx context.Context, max int) ([]repo.DeadLetter, error) {
+	s.pulls++
+	if len(s.pages) == 0 {
+		return nil, nil
+	}
+	page := s.pages[0]
+	s.pages = s.pages[1:]
+	if len(page) > max {
+		return nil, fmt.Errorf("page of %d exceeds max %d", len(page), max)
+	}
+	return page, nil
+}
+
+func (s *scriptedDeadLetters) ExtendDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error {
+	s.extended = append(s.extended, sortedAckIDs(msgs))
+	return nil
+}
+
+func (s *scriptedDeadLetters) AckDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error {
+	s.acked = append(s.acked, sortedAckIDs(msgs)...)
+	return nil
+}
+
+func (s *scriptedDeadLetters) ReleaseDeadLetters(ctx context.Context, msgs []repo.DeadLetter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.released = append(s.released, sortedAckIDs(msgs)...)
+	return nil
+}
+
+func (s *scriptedDeadLetters) Republish(msg repo.DeadLetter) error { return nil }
+
+func sortedAckIDs(msgs []repo.DeadLetter) []string {
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.AckID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func deadLetters(ids ...string) []repo.DeadLetter {
+	out := make([]repo.DeadLetter, 0, len(ids))
+	for i, id := range ids {
+		out = append(out, repo.DeadLetter{AckID: "ack-" + id, MessageID: id, PublishTime: time.Unix(int64(i), 0)})
+	}
+	return out
+}
+
+func pageIDs(page []deadLetterView) []string {
+	var ids []string
+	for _, v := range page {
+		ids = append(ids, v.msg.MessageID)
+	}
+	return ids
+}
+
+func TestPageDeadLetters_HoldsAndRefreshesUnackedPages(t *testing.T) {
+	src := &scriptedDeadLetters{pages: [][]repo.DeadLetter{deadLetters("a", "b"), deadLetters("c")}}
+	var seen [][]string
+	err := pageDeadLetters(context.Background(), src, 0, func(ctx context.Context, _ deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+		seen = append(seen, pageIDs(page))
+		return map[string]bool{"b": true}, false, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"a", "b"}, {"c"}}, seen)
+	require.Equal(t, 3, src.pulls)
+	// Leases of held messages are refreshed before each pull.
+	require.Equal(t, [][]string{{}, {"ack-a"}, {"ack-a", "ack-c"}}, src.extended)
+	require.Equal(t, []string{"ack-a", "ack-c"}, src.released)
+}
+
+func TestPageDeadLetters_Redelivery(t *testing.T) {
+	again := func(id, ackID string) repo.DeadLetter {
+		return repo.DeadLetter{AckID: ackID, MessageID: id}
+	}
+	src := &scriptedDeadLetters{pages: [][]repo.DeadLetter{
+		deadLetters("a", "b"),
+		{again("a", "ack-a2"), again("b", "ack-b2")},
+	}}
+	pages := 0
+	err := pageDeadLetters(context.Background(), src, 0, func(ctx context.Context, _ deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+		pages++
+		return map[string]bool{"b": true}, false, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, pages, "redelivered messages are not shown twice")
+	// b was acked already, so its new ack ID is acked too; a is released
+	// with its newest ack ID.
+	require.Equal(t, []string{"ack-b2"}, src.acked)
+	require.Equal(t, []string{"ack-a2"}, src.released)
+}
+
+func TestPageDeadLetters_StopsWhenDoneOrAtLimit(t *testing.T) {
+	src := &scriptedDeadLetters{pages: [][]repo.DeadLetter{deadLetters("a", "b"), deadLetters("c", "d")}}
+	err := pageDeadLetters(context.Background(), src, 0, func(ctx context.Context, _ deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+		return nil, true, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, src.pulls)
+	require.Equal(t, []string{"ack-a", "ack-b"}, src.released)
+
+	src = &scriptedDeadLetters{pages: [][]repo.DeadLetter{deadLetters("a", "b"), deadLetters("c"), deadLetters("d")}}
+	var seen []string
+	err = pageDeadLetters(context.Background(), src, 3, func(ctx context.Context, _ deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+		seen = append(seen, pageIDs(page)...)
+		return nil, false, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, seen)
+	require.Equal(t, 2, src.pulls)
+}
+
+func TestPageDeadLetters_ReleasesHeldOnError(t *testing.T) {
+	src := &scriptedDeadLetters{pages: [][]repo.DeadLetter{deadLetters("a", "b")}}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := pageDeadLetters(ctx, src, 0, func(ctx context.Context, _ deadLetterSource, page []deadLetterView) (map[string]bool, bool, error) {
+		// An interrupt cancels ctx while a page is being handled.
+		cancel()
+		return map[string]bool{"a": true}, false, ctx.Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []string{"ack-b"}, src.released)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"project/internal/model"
+	"project/internal/repo"
+	"project/pkg/gateway"
+	pb "project/pkg/pb"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// e2eHarness runs the dev command's fx graph in process: gRPC is served over
+// bufconn and the gateway through httptest, so requests go through the real
+// middleware, routes, interceptors and error translation.
+type e2eHarness struct {
+	t        *testing.T
+	url      string
+	store    *repo.MemoryTransferRepo
+	sessions *repo.MemoryTokenStore
+}
+
+func newE2EHarness(t *testing.T) *e2eHarness {
+	t.Helper()
+	var (
+		srv      *GRPCServer
+		gw       *HTTPGateway
+		store    *repo.MemoryTransferRepo
+		sessions *repo.MemoryTokenStore
+	)
+	app := fx.New(
+		fx.NopLogger,
+		memoryProviders(),
+		apiProviders(),
+		fx.Supply(model.SeedUsersInput{}),
+		fx.Invoke(seedDevData, RegisterMemoryBrokerLifecycle, RegisterEventRelayLifecycle),
+		fx.Populate(&srv, &gw, &store, &sessions),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, app.Start(ctx))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(ctx))
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	gatewayCtx, stopGateway := context.WithCancel(context.Background())
+	t.Cleanup(stopGateway)
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+	}
+	require.NoError(t, registerGatewayHandlers(gatewayCtx, gw.Mux, "passthrough:///bufnet", opts))
+
+	ts := httptest.NewServer(gw.Server.Handler)
+	t.Cleanup(ts.Close)
+	return &e2eHarness{t: t, url: ts.URL, store: store, sessions: sessions}
+}
+
+// do sends body (JSON, may be empty) and returns the status code and the raw
+// response body.
+func (h *e2eHarness) do(method, path, token, body string, header ...string) (int, []byte) {
+	h.t.Helper()
+	req, err := http.NewRequest(method, h.url+path, strings.NewReader(body))
+	require.NoError(h.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(h.t, err)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(h.t, err)
+	return resp.StatusCode, raw
+}
+
+// call expects a 200 and decodes the body into out.
+func (h *e2eHarness) call(method, path, token, body string, out proto.Message) {
+	h.t.Helper()
+	code, raw := h.do(method, path, token, body)
+	require.Equal(h.t, http.StatusOK, code, string(raw))
+	require.NoError(h.t, protojson.Unmarshal(raw, out))
+}
+
+// fail expects an error response and decodes its body.
+func (h *e2eHarness) fail(wantStatus int, method, path, token, body string, header ...string) gateway.ErrorBody {
+	h.t.Helper()
+	code, raw := h.do(method, path, token, body, header...)
+	require.Equal(h.t, wantStatus, code, string(raw))
+	var out gateway.ErrorBody
+	require.NoError(h.t, json.Unmarshal(raw, &out))
+	return out
+}
+
+func (h *e2eHarness) login(userID int64, password string) string {
+	h.t.Helper()
+	var resp pb.LoginResponse
+	h.call(http.MethodPost, "/v1/auth/login", "", fmt.Sprintf(`{"username":%d,"password":%q}`, userID, password), &resp)
+	require.NotEmpty(h.t, resp.AccessToken)
+	return resp.AccessToken
+}
+
+func TestE2E_TransferFlow(t *testing.T) {
+	h := newE2EHarness(t)
+	bob := h.login(2, "password456")
+
+	var balance pb.GetBalanceResponse
+	h.call(http.MethodGet, "/v1/transfer/balance", bob, "", &balance)
+	require.EqualValues(t, 2, balance.UserId)
+	require.EqualValues(t, 5000, balance.Balance)
+
+	var sent pb.SendMoneyResponse
+	h.call(http.MethodPost, "/v1/transfer/send", bob, `{"to":1,"amount":700}`, &sent)
+	require.True(t, sent.Success)
+	require.NotZero(t, sent.TransactionId)
+	h.call(http.MethodPost, "/v1/transfer/send", bob, `{"to":3,"amount":"300"}`, &sent)
+
+	h.call(http.MethodGet, "/v1/transfer/balance", bob, "", &balance)
+	require.EqualValues(t, 4000, balance.Balance)
+	alice, err := h.store.GetBalance(context.Background(), 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 10700, alice)
+
+	var history pb.ListTransactionsResponse
+	h.call(http.MethodGet, "/v1/transfer/transactions", bob, "", &history)
+	require.EqualValues(t, 2, history.Number)
+	require.Len(t, history.Transactions, 2)
+	require.EqualValues(t, 1, history.Transactions[0].To)
+	require.EqualValues(t, 700, history.Transactions[0].Amount)
+	require.EqualValues(t, sent.TransactionId, history.Transactions[1].Id)
+
+	// The relay publishes the TransferCompleted events from the outbox and
+	// the in-process broker delivers them to the consumer, which records
+	// them as processed: one on bob's stream and one on carol's.
+	for _, account := range []int64{2, 3} {
+		require.Eventually(t, func() bool {
+			id := fmt.Sprintf("transfer-%d-account-%d", sent.TransactionId, account)
+			done, err := h.sessions.IsEventProcessed(context.Background(), id)
+			return err == nil && done
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	var logout pb.LogoutResponse
+	h.call(http.MethodPost, "/v1/auth/logout", bob, "{}", &logout)
+	require.True(t, logout.Success)
+	body := h.fail(http.StatusUnauthorized, http.MethodGet, "/v1/transfer/balance", bob, "")
+	require.Equal(t, "UNAUTHENTICATED", body.Code)
+}
+
+func TestE2E_SendMoneyBatch(t *testing.T) {
+	h := newE2EHarness(t)
+	alice := h.login(1, "password123")
+
+	body := h.fail(http.StatusNotFound, http.MethodPost, "/v1/transfer/send-batch", alice, `{"lines":[{"to":2,"amount":100},{"to":99,"amount":100}]}`)
+	require.Equal(t, "USER_NOT_FOUND", body.Reason)
+	require.True(t, strings.HasPrefix(body.Message, "line 1:"), body.Message)
+
+	var resp pb.SendMoneyBatchResponse
+	h.call(http.MethodPost, "/v1/transfer/send-batch", alice, `{"lines":[{"to":2,"amount":100},{"to":3,"amount":"250"}]}`, &resp)
+	require.EqualValues(t, 350, resp.Total)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, fmt.Sprintf("batch-%d", resp.Results[0].TransactionId), resp.BatchId)
+
+	var balance pb.GetBalanceResponse
+	h.call(http.MethodGet, "/v1/transfer/balance", alice, "", &balance)
+	require.EqualValues(t, 9650, balance.Balance)
+
+	require.Eventually(t, func() bool {
+		done, err := h.sessions.IsEventProcessed(context.Background(), resp.BatchId)
+		return err == nil && done
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestE2E_Errors(t *testing.T) {
+	h := newE2EHarness(t)
+	bob := h.login(2, "password456")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantReason string
+	}{
+		{name: "bad password", method: http.MethodPost, path: "/v1/auth/login", body: `{"username":2,"password":"nope"}`,
+			wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHENTICATED", wantReason: "INVALID_CREDENTIALS"},
+		{name: "no token", method: http.MethodGet, path: "/v1/transfer/balance",
+			wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHENTICATED"},
+		{name: "garbage token", method: http.MethodGet, path: "/v1/transfer/balance", token: "abc",
+			wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHENTICATED"},
+		{name: "insufficient funds", method: http.MethodPost, path: "/v1/transfer/send", token: bob, body: `{"to":1,"amount":5001}`,
+			wantStatus: http.StatusBadRequest, wantCode: "FAILED_PRECONDITION", wantReason: "INSUFFICIENT_FUNDS"},
+		{name: "self transfer", method: http.MethodPost, path: "/v1/transfer/send", token: bob, body: `{"to":2,"amount":1}`,
+			wantStatus: http.StatusBadRequest, wantCode: "INVALID_ARGUMENT", wantReason: "SELF_TRANSFER"},
+		{name: "zero amount", method: http.MethodPost, path: "/v1/transfer/send", token: bob, body: `{"to":1}`,
+			wantStatus: http.StatusBadRequest, wantCode: "INVALID_ARGUMENT", wantReason: "INVALID_AMOUNT"},
+		{name: "unknown recipient", method: http.MethodPost, path: "/v1/transfer/send", token: bob, body: `{"to":99,"amount":1}`,
+			wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND", wantReason: "USER_NOT_FOUND"},
+		{name: "malformed body", method: http.MethodPost, path: "/v1/transfer/send", token: bob, body: `{"to":`,
+			wantStatus: http.StatusBadRequest, wantCode: "INVALID_ARGUMENT"},
+		{name: "admin route as user", method: http.MethodGet, path: "/v1/admin/users", token: bob,
+			wantStatus: http.StatusForbidden, wantCode: "PERMISSION_DENIED"},
+		{name: "unknown route", method: http.MethodGet, path: "/v1/nope", token: bob,
+			wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestID := "req-" + strings.ReplaceAll(tt.name, " ", "-")
+			body := h.fail(tt.wantStatus, tt.method, tt.path, tt.token, tt.body, "X-Request-Id", requestID)
+			require.Equal(t, tt.wantCode, body.Code)
+			require.Equal(t, tt.wantReason, body.Reason)
+			require.Equal(t, requestID, body.RequestID)
+		})
+	}
+
+	balance, err := h.store.GetBalance(context.Background(), 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 5000, balance)
+}
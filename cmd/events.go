@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"golang.org/x/time/rate"
+)
+
+// A transaction is published once on the sender's stream and once on the
+// recipient's, so a batch of maxBackfillBatch transactions fits in one Pub/Sub
+// publish request (at most 1000 messages).
+const (
+	eventsPerTransaction = 2
+	maxBackfillBatch     = 500
+)
+
+func NewEventsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Regenerate and republish domain events",
+	}
+	cmd.AddCommand(newEventsBackfillCommand())
+	return cmd
+}
+
+type backfillOptions struct {
+	fromID     uint
+	toID       uint
+	since      string
+	until      string
+	batchSize  int
+	rate       float64
+	dryRun     bool
+	checkpoint string
+}
+
+// backfillCheckpoint is persisted after every published batch. Range records
+// the flags it was written for so a resume with different bounds is refused.
+type backfillCheckpoint struct {
+	Range     string    `json:"range"`
+	LastID    uint      `json:"last_id"`
+	Published int       `json:"published"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newEventsBackfillCommand() *cobra.Command {
+	var opts backfillOptions
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Republish TransferCompleted events from the transactions table",
+		Long: `Scans the transactions table in ID order and republishes the TransferCompleted
+events of every row, one on the sender's stream and one on the recipient's.
+Event IDs are derived from the transaction and account IDs, so consumers drop
+events they have already processed.
+
+Progress is written to the checkpoint file after every batch. Re-running the
+same command resumes after the last published transaction.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackfill(opts)
+		},
+	}
+	cmd.Flags().UintVar(&opts.fromID, "from-id", 0, "first transaction ID to include")
+	cmd.Flags().UintVar(&opts.toID, "to-id", 0, "last transaction ID to include (0 = no limit)")
+	cmd.Flags().StringVar(&opts.since, "since", "", "only transactions created at or after this RFC3339 time")
+	cmd.Flags().StringVar(&opts.until, "until", "", "only transactions created before this RFC3339 time")
+	cmd.Flags().IntVar(&opts.batchSize, "batch-size", 500, "transactions read and published per batch (max 500)")
+	cmd.Flags().Float64Var(&opts.rate, "rate", 200, "maximum events published per second (0 = unlimited)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "scan and report without publishing or writing the checkpoint")
+	cmd.Flags().StringVar(&opts.checkpoint, "checkpoint", "backfill-checkpoint.json", "file used to record and resume progress")
+	return cmd
+}
+
+func runBackfill(opts backfillOptions) error {
+	if opts.batchSize <= 0 || opts.batchSize > maxBackfillBatch {
+		return fmt.Errorf("--batch-size must be between 1 and %d", maxBackfillBatch)
+	}
+	scan := model.TransactionScan{ToID: opts.toID}
+	var err error
+	if opts.since != "" {
+		if scan.Since, err = time.Parse(time.RFC3339, opts.since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if opts.until != "" {
+		if scan.Until, err = time.Parse(time.RFC3339, opts.until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	rangeKey := fmt.Sprintf("from=%d to=%d since=%s until=%s", opts.fromID, opts.toID, opts.since, opts.until)
+	cp, err := loadCheckpoint(opts.checkpoint)
+	if err != nil {
+		return err
+	}
+	if cp.Range != "" && cp.Range != rangeKey {
+		return fmt.Errorf("checkpoint %s was written for %q; remove it or pass another --checkpoint", opts.checkpoint, cp.Range)
+	}
+	cp.Range = rangeKey
+
+	afterID := cp.LastID
+	if opts.fromID > 0 && opts.fromID-1 > afterID {
+		afterID = opts.fromID - 1
+	}
+	if cp.LastID > 0 {
+		log.Printf("[Backfill] resuming after transaction %d (%d already published)", cp.LastID, cp.Published)
+	}
+
+	var transfers *repo.GormTransferRepo
+	var ps *repo.PubSub
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(
+			config.LoadConfig,
+			repo.NewPostgresDB,
+			repo.NewPostgresTransferRepo,
+			repo.NewPubSubClient,
+		),
+		fx.Populate(&transfers, &ps),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	defer ps.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	limit := rate.Inf
+	if opts.rate > 0 {
+		limit = rate.Limit(opts.rate)
+	}
+	limiter := rate.NewLimiter(limit, eventsPerTransaction*opts.batchSize)
+
+	scanned := 0
+	for {
+		txs, err := transfers.ScanTransactions(ctx, afterID, scan, opts.batchSize)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("scan transactions after %d: %w", afterID, err)
+		}
+		if len(txs) == 0 {
+			break
+		}
+		scanned += len(txs)
+		first, last := txs[0].ID, txs[len(txs)-1].ID
+
+		if opts.dryRun {
+			log.Printf("[Backfill] dry-run: would publish events for %d transaction(s) %d..%d", len(txs), first, last)
+			afterID = last
+			continue
+		}
+
+		msgs := make([]repo.Message, 0, eventsPerTransaction*len(txs))
+		for _, tx := range txs {
+			m, err := repo.TransferCompletedMessages(tx)
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, m...)
+		}
+
+		if err := limiter.WaitN(ctx, len(msgs)); err != nil {
+			break
+		}
+		if err := ps.PublishBatch(msgs); err != nil {
+			return fmt.Errorf("publish transactions %d..%d: %w", first, last, err)
+		}
+
+		afterID = last
+		cp.LastID = last
+		cp.Published += len(msgs)
+		if err := saveCheckpoint(opts.checkpoint, cp); err != nil {
+			return err
+		}
+		log.Printf("[Backfill] published %d event(s) for transactions %d..%d (total %d)", len(msgs), first, last, cp.Published)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		log.Printf("[Backfill] interrupted after transaction %d; re-run the same command to resume", cp.LastID)
+		return nil
+	}
+	if opts.dryRun {
+		log.Printf("[Backfill] dry-run complete: %d transaction(s) in range", scanned)
+		return nil
+	}
+	log.Printf("[Backfill] complete: %d event(s) published", cp.Published)
+	return nil
+}
+
+func loadCheckpoint(path string) (backfillCheckpoint, error) {
+	var cp backfillCheckpoint
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("parse checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// saveCheckpoint writes through a temp file so an interrupted write never
+// leaves a truncated checkpoint behind.
+func saveCheckpoint(path string, cp backfillCheckpoint) error {
+	cp.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -8,9 +8,12 @@ import (
 
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"project/config"
 	grpcapi "project/internal/api"
+	"project/pkg/interceptor"
+	"project/pkg/tlsreload"
 
 	pb "project/pkg/pb"
 )
@@ -18,23 +21,50 @@ import (
 type GRPCServer struct {
 	*grpc.Server
 	Addr string
+	tls  *tlsreload.Reloader
+	cfg  config.ServerTLSConfig
 }
 
-func NewGRPCServer(svc *grpcapi.Transfer, auth *grpcapi.Auth, config *config.Config, authInterceptor grpc.UnaryServerInterceptor) *GRPCServer {
-	s := grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor),
-	)
+func NewGRPCServer(svc *grpcapi.Transfer, auth *grpcapi.Auth, apiKeys *grpcapi.APIKeys, admin *grpcapi.Admin, config *config.Config, authInterceptor grpc.UnaryServerInterceptor) (*GRPCServer, error) {
+	requestInfo, err := interceptor.NewRequestInfoInterceptor(config.Server.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptor.NewErrorInterceptor(), requestInfo, authInterceptor),
+	}
+
+	var reloader *tlsreload.Reloader
+	if tlsCfg := config.Server.TLS; tlsCfg.Enabled() {
+		var err error
+		reloader, err = tlsreload.New(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	} else {
+		log.Println("[WARN] gRPC server is serving plaintext; set GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE to enable TLS")
+	}
+
+	s := grpc.NewServer(opts...)
 	pb.RegisterTransferServiceServer(s, svc)
 	pb.RegisterAuthServiceServer(s, auth)
+	pb.RegisterAPIKeyServiceServer(s, apiKeys)
+	pb.RegisterAdminServiceServer(s, admin)
 	return &GRPCServer{
 		Server: s,
 		Addr:   config.Server.GRPCAddr,
-	}
+		tls:    reloader,
+		cfg:    config.Server.TLS,
+	}, nil
 }
 
 func RegisterGRPCLifecycle(lc fx.Lifecycle, srv *GRPCServer) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			if srv.tls != nil {
+				srv.tls.Start(srv.cfg.ReloadInterval)
+			}
 			go func() {
 				lis, err := net.Listen("tcp", srv.Addr)
 				if err != nil {
@@ -51,6 +81,9 @@ func RegisterGRPCLifecycle(lc fx.Lifecycle, srv *GRPCServer) {
 		OnStop: func(ctx context.Context) error {
 			log.Println("stopping gRPC server...")
 			srv.GracefulStop()
+			if srv.tls != nil {
+				srv.tls.Stop()
+			}
 			return nil
 		},
 	})
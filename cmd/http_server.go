@@ -6,57 +6,134 @@ import (
 	"log"
 	"net/http"
 	pb "project/pkg/pb"
+	"time"
 
 	"project/config"
+	"project/internal/utils"
+	"project/pkg/gateway"
+	"project/pkg/tlsreload"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type HTTPGateway struct {
 	Mux      *runtime.ServeMux
+	Server   *http.Server
 	HTTPAddr string
 	GRPCAddr string
+
+	grpcCreds      credentials.TransportCredentials
+	reloaders      []*tlsreload.Reloader
+	reloadInterval time.Duration
 }
 
-func NewHTTPGateway(config *config.Config) *HTTPGateway {
+func NewHTTPGateway(config *config.Config, keys *utils.KeySet) (*HTTPGateway, error) {
 	fmt.Println(config.Gateway.GRPCAddr)
-	return &HTTPGateway{
-		Mux:      runtime.NewServeMux(),
-		HTTPAddr: config.Gateway.HTTPAddr,
-		GRPCAddr: config.Gateway.GRPCAddr,
+	mux := gateway.NewServeMux()
+	if err := gateway.RegisterDocs(mux); err != nil {
+		return nil, err
+	}
+	jwks, err := keys.JWKS()
+	if err != nil {
+		return nil, err
+	}
+	if err := gateway.RegisterJWKS(mux, jwks); err != nil {
+		return nil, err
+	}
+	gw := &HTTPGateway{
+		Mux:            mux,
+		Server:         gateway.NewServer(config.Gateway, mux),
+		HTTPAddr:       config.Gateway.HTTPAddr,
+		GRPCAddr:       config.Gateway.GRPCAddr,
+		reloadInterval: config.Gateway.TLS.ReloadInterval,
+	}
+
+	creds, clientReloader, err := grpcClientCredentials(config.Gateway.GRPCTLS)
+	if err != nil {
+		return nil, err
 	}
+	gw.grpcCreds = creds
+	if clientReloader != nil {
+		gw.reloaders = append(gw.reloaders, clientReloader)
+	}
+
+	if tlsCfg := config.Gateway.TLS; tlsCfg.Enabled() {
+		serverReloader, err := tlsreload.New(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		gw.Server.TLSConfig = serverReloader.ServerConfig()
+		gw.reloaders = append(gw.reloaders, serverReloader)
+	}
+	return gw, nil
+}
+
+// grpcClientCredentials returns the transport credentials used to dial the
+// gRPC server, plus the reloader behind them when TLS is enabled.
+func grpcClientCredentials(cfg config.ClientTLSConfig) (credentials.TransportCredentials, *tlsreload.Reloader, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil, nil
+	}
+	r, err := tlsreload.New(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return credentials.NewTLS(r.ClientConfig(cfg.ServerName)), r, nil
+}
+
+// registerGatewayHandlers proxies every service on mux to the gRPC server at
+// endpoint. The connections close when ctx is done.
+func registerGatewayHandlers(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	for _, register := range []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		pb.RegisterTransferServiceHandlerFromEndpoint,
+		pb.RegisterAuthServiceHandlerFromEndpoint,
+		pb.RegisterAPIKeyServiceHandlerFromEndpoint,
+		pb.RegisterAdminServiceHandlerFromEndpoint,
+	} {
+		if err := register(ctx, mux, endpoint, opts); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func RegisterHTTPLifecycle(lc fx.Lifecycle, gw *HTTPGateway) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			for _, r := range gw.reloaders {
+				r.Start(gw.reloadInterval)
+			}
 			go func() {
 				gatewayCtx := context.Background()
-				opts := []grpc.DialOption{grpc.WithInsecure()}
+				opts := []grpc.DialOption{grpc.WithTransportCredentials(gw.grpcCreds)}
 
-				if err := pb.RegisterTransferServiceHandlerFromEndpoint(
-					gatewayCtx, gw.Mux, gw.GRPCAddr, opts,
-				); err != nil {
-					log.Println(err)
-				}
-
-				if err := pb.RegisterAuthServiceHandlerFromEndpoint(
-					gatewayCtx, gw.Mux, gw.GRPCAddr, opts,
-				); err != nil {
+				if err := registerGatewayHandlers(gatewayCtx, gw.Mux, gw.GRPCAddr, opts); err != nil {
 					log.Println(err)
 				}
 
 				log.Printf("HTTP Gateway listening on %s (proxy to %s)", gw.HTTPAddr, gw.GRPCAddr)
-				if err := http.ListenAndServe(gw.HTTPAddr, gw.Mux); err != nil {
+				var err error
+				if gw.Server.TLSConfig != nil {
+					err = gw.Server.ListenAndServeTLS("", "")
+				} else {
+					err = gw.Server.ListenAndServe()
+				}
+				if err != nil && err != http.ErrServerClosed {
 					log.Println(err)
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			return nil
+			err := gw.Server.Shutdown(ctx)
+			for _, r := range gw.reloaders {
+				r.Stop()
+			}
+			return err
 		},
 	})
 }
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"crypto"
+	"errors"
+	"log"
+
+	"project/config"
+	"project/internal/utils"
+)
+
+// NewSigningKey loads the JWT signing key from JWT_SIGNING_KEY_FILE. It is
+// required: replicas must share the key, and tokens must survive a restart.
+func NewSigningKey(config *config.Config) (crypto.Signer, error) {
+	if config.JWT.SigningKeyFile == "" {
+		return nil, errors.New("JWT_SIGNING_KEY_FILE is not set")
+	}
+	return utils.LoadSigningKey(config.JWT.SigningKeyFile)
+}
+
+// newDevSigningKey is NewSigningKey for the dev command, which falls back to
+// a throwaway Ed25519 key that invalidates tokens on every restart.
+func newDevSigningKey(config *config.Config) (crypto.Signer, error) {
+	if config.JWT.SigningKeyFile != "" {
+		return utils.LoadSigningKey(config.JWT.SigningKeyFile)
+	}
+	log.Println("[WARN] JWT_SIGNING_KEY_FILE not set; signing tokens with an ephemeral Ed25519 key")
+	return utils.GenerateEd25519Key()
+}
+
+// NewTokenKeys builds the JWT key set from signer and the verification keys.
+func NewTokenKeys(config *config.Config, signer crypto.Signer) (*utils.KeySet, error) {
+	var verification []crypto.PublicKey
+	for _, path := range config.JWT.VerificationKeyFiles {
+		pub, err := utils.LoadVerificationKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, pub)
+	}
+	return utils.NewKeySet(config.JWT.Issuer, config.JWT.Audience, signer, verification...)
+}
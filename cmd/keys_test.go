@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+
+	"project/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSigningKey_RequiresKeyFile(t *testing.T) {
+	cfg := &config.Config{}
+	_, err := NewSigningKey(cfg)
+	require.ErrorContains(t, err, "JWT_SIGNING_KEY_FILE")
+
+	signer, err := newDevSigningKey(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, signer)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net/http"
+
+	"project/config"
+
+	"go.uber.org/fx"
+)
+
+// RegisterMetricsLifecycle serves expvar counters at /debug/vars on
+// config.Metrics.Addr. An empty address disables the listener.
+func RegisterMetricsLifecycle(lc fx.Lifecycle, config *config.Config) {
+	if config.Metrics.Addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	srv := &http.Server{Addr: config.Metrics.Addr, Handler: mux}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				log.Printf("Metrics listening on %s/debug/vars", config.Metrics.Addr)
+				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Println(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+	})
+}
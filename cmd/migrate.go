@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"project/config"
+	"project/internal/repo"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+func NewMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema",
+		Long: `Applies the SQL migrations embedded in this binary. An advisory lock makes
+concurrent runs safe: replicas started together apply each migration once.`,
+	}
+	cmd.AddCommand(
+		newMigrateUpCommand(),
+		newMigrateDownCommand(),
+		newMigrateStatusCommand(),
+		newMigrateCreateCommand(),
+	)
+	return cmd
+}
+
+func newMigrateUpCommand() *cobra.Command {
+	var steps int
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(func(ctx context.Context, m *repo.Migrator) error {
+				applied, err := m.Up(ctx, steps)
+				for _, mig := range applied {
+					log.Printf("[Migrate] applied %04d_%s", mig.Version, mig.Name)
+				}
+				if err == nil && len(applied) == 0 {
+					log.Printf("[Migrate] schema is up to date")
+				}
+				return err
+			})
+		},
+	}
+	cmd.Flags().IntVar(&steps, "steps", 0, "apply at most this many migrations (0 = all)")
+	return cmd
+}
+
+func newMigrateDownCommand() *cobra.Command {
+	var steps int
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back the most recent migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps <= 0 {
+				return fmt.Errorf("--steps must be at least 1")
+			}
+			return withMigrator(func(ctx context.Context, m *repo.Migrator) error {
+				reverted, err := m.Down(ctx, steps)
+				for _, mig := range reverted {
+					log.Printf("[Migrate] rolled back %04d_%s", mig.Version, mig.Name)
+				}
+				if err == nil && len(reverted) == 0 {
+					log.Printf("[Migrate] nothing to roll back")
+				}
+				return err
+			})
+		},
+	}
+	cmd.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	return cmd
+}
+
+func newMigrateStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List migrations and whether they are applied",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(func(ctx context.Context, m *repo.Migrator) error {
+				states, err := m.Status(ctx)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+				for _, s := range states {
+					applied := "pending"
+					if s.AppliedAt != nil {
+						applied = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+					}
+					fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+				}
+				return w.Flush()
+			})
+		},
+	}
+}
+
+func newMigrateCreateCommand() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Write an empty up/down migration pair",
+		Long: `Writes the next numbered up/down pair to the migrations directory. Migrations
+are embedded at build time, so rebuild before running them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			up, down, err := repo.CreateMigration(dir, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), up)
+			fmt.Fprintln(cmd.OutOrStdout(), down)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", repo.MigrationsDir, "migrations directory")
+	return cmd
+}
+
+func withMigrator(fn func(ctx context.Context, m *repo.Migrator) error) error {
+	var db *gorm.DB
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(
+			config.LoadConfig,
+			repo.OpenPostgres,
+		),
+		fx.Populate(&db),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	m, err := repo.NewMigrator(sqlDB)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return fn(ctx, m)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+
+	"project/config"
+	"project/internal/repo"
+
+	"go.uber.org/fx"
+)
+
+// Defaults used in place of non-positive settings.
+const (
+	defaultRelayBatchSize = 100
+	defaultRelayInterval  = 100 * time.Millisecond
+)
+
+// maxRelayBackoff caps how long the relay waits after repeated failures.
+const maxRelayBackoff = 30 * time.Second
+
+var relayFailures = expvar.NewInt("outbox_relay_failures")
+
+// EventRelay publishes the events transfers write to the outbox.
+type EventRelay interface {
+	RelayEvents(ctx context.Context, limit int, send func(msgs []repo.Message) error) (int, error)
+}
+
+// EventSender is the broker the relay publishes to.
+type EventSender interface {
+	PublishBatch(msgs []repo.Message) error
+}
+
+// RegisterEventRelayLifecycle publishes the outbox every
+// config.PubSub.PublishFlushInterval, config.PubSub.PublishBatchSize messages
+// per request, until the app stops. Whatever is left is published by the next
+// replica to run the relay. After a failed publish the messages stay in the
+// outbox and the relay retries them, backing off up to maxRelayBackoff.
+func RegisterEventRelayLifecycle(lc fx.Lifecycle, relay EventRelay, sender EventSender, config *config.Config) {
+	batch := config.PubSub.PublishBatchSize
+	if batch <= 0 {
+		batch = defaultRelayBatchSize
+	}
+	interval := config.PubSub.PublishFlushInterval
+	if interval <= 0 {
+		interval = defaultRelayInterval
+	}
+	backoff := &relayBackoff{base: interval, max: maxRelayBackoff, now: time.Now}
+	registerTicker(lc, interval, "EventRelay", func(ctx context.Context) error {
+		return backoff.run(func() error {
+			for {
+				n, err := relay.RelayEvents(ctx, batch, sender.PublishBatch)
+				if err != nil || n < batch {
+					return err
+				}
+			}
+		})
+	})
+}
+
+// relayBackoff skips relay runs after a failure, doubling the wait from base
+// up to max with each consecutive failure. A success resets it.
+type relayBackoff struct {
+	base, max time.Duration
+	now       func() time.Time
+	failures  int
+	next      time.Time
+}
+
+func (b *relayBackoff) run(fn func() error) error {
+	if b.now().Before(b.next) {
+		return nil
+	}
+	if err := fn(); err != nil {
+		b.failures++
+		relayFailures.Add(1)
+		delay := b.delay()
+		b.next = b.now().Add(delay)
+		return fmt.Errorf("%w (attempt %d, retrying in %s)", err, b.failures, delay)
+	}
+	b.failures = 0
+	b.next = time.Time{}
+	return nil
+}
+
+func (b *relayBackoff) delay() time.Duration {
+	delay := b.base
+	for i := 1; i < b.failures && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	return delay
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayBackoff(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := &relayBackoff{base: time.Second, max: 4 * time.Second, now: func() time.Time { return now }}
+	calls := 0
+	fail := func() error { calls++; return errors.New("broker down") }
+
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		require.Error(t, b.run(fail))
+		require.Equal(t, now.Add(wait), b.next)
+
+		// Runs before the wait is over are skipped.
+		now = now.Add(wait - time.Millisecond)
+		require.NoError(t, b.run(fail))
+		now = now.Add(time.Millisecond)
+	}
+	require.Equal(t, 4, calls)
+
+	// A success resets the backoff.
+	require.NoError(t, b.run(func() error { return nil }))
+	require.Error(t, b.run(fail))
+	require.Equal(t, now.Add(time.Second), b.next)
+}
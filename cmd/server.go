@@ -17,45 +17,92 @@ func NewServeCommand() *cobra.Command {
 		Short: "Start HTTP + gRPC servers",
 		Run: func(cmd *cobra.Command, args []string) {
 			app := fx.New(
+				dataProviders(),
 				fx.Provide(
-					NewHTTPGateway,
-					NewGRPCServer,
-					repo.NewPostgresDB,
-					fx.Annotate(
-						repo.NewPostgresTransferRepo,
-						fx.As(new(service.TransferRepo)),
-						fx.As(new(service.DBClient)),
-					),
-					fx.Annotate(
-						service.NewTransferService,
-						fx.As(new(grpcapi.TransferService)),
-					),
+					NewSigningKey,
 					fx.Annotate(
 						repo.NewPubSubClient,
-						fx.As(new(grpcapi.Publisher)),
+						fx.As(new(EventSender)),
 					),
-					config.LoadConfig,
-					service.NewAuthService,
-
 					fx.Annotate(
 						repo.NewRedisClient,
 						fx.As(new(service.RedisClient)),
 						fx.As(new(interceptor.RedisToken)),
 					),
-					interceptor.NewAuthInterceptor,
 					fx.Annotate(
-						service.NewAuthService,
-						fx.As(new(grpcapi.AuthService)),
+						repo.NewPostgresAPIKeyRepo,
+						fx.As(new(service.APIKeyRepo)),
 					),
-					grpcapi.NewTransferService,
-					grpcapi.NewAuth,
 				),
+				apiProviders(),
 				fx.Invoke(
+					RegisterEventRelayLifecycle,
+					RegisterSweeperLifecycle,
+					RegisterAuditChainerLifecycle,
 					RegisterHTTPLifecycle,
 					RegisterGRPCLifecycle,
+					RegisterMetricsLifecycle,
 				),
 			)
 			app.Run()
 		},
 	}
 }
+
+// apiProviders supplies the services, handlers, interceptors and servers on
+// top of whichever storage, token store and publisher the command provides.
+func apiProviders() fx.Option {
+	return fx.Provide(
+		NewHTTPGateway,
+		NewGRPCServer,
+		fx.Annotate(
+			service.NewTransferService,
+			fx.As(new(grpcapi.TransferService)),
+		),
+		fx.Annotate(
+			NewTokenKeys,
+			fx.As(fx.Self()),
+			fx.As(new(service.TokenIssuer)),
+			fx.As(new(interceptor.TokenValidator)),
+		),
+		fx.Annotate(
+			service.NewAPIKeyService,
+			fx.As(new(grpcapi.APIKeyService)),
+			fx.As(new(interceptor.APIKeyAuthenticator)),
+		),
+		interceptor.NewAuthInterceptor,
+		fx.Annotate(
+			service.NewAuthService,
+			fx.As(new(grpcapi.AuthService)),
+		),
+		grpcapi.NewTransferService,
+		grpcapi.NewAuth,
+		grpcapi.NewAPIKeys,
+		fx.Annotate(
+			service.NewAdminService,
+			fx.As(new(grpcapi.AdminService)),
+		),
+		grpcapi.NewAdmin,
+	)
+}
+
+// dataProviders supplies the config, the database and the Postgres
+// repository. The server and the offline CLI commands share it.
+func dataProviders() fx.Option {
+	return fx.Provide(
+		config.LoadConfig,
+		repo.NewPostgresDB,
+		fx.Annotate(
+			repo.NewPostgresTransferRepo,
+			fx.As(new(service.TransferRepo)),
+			fx.As(new(service.DBClient)),
+			fx.As(new(service.AdminRepo)),
+			fx.As(new(service.UserRepo)),
+			fx.As(new(interceptor.AccountStatus)),
+			fx.As(new(service.AuditRecorder)),
+			fx.As(new(HotAccountSweeper)),
+			fx.As(new(AuditChainer)),
+			fx.As(new(EventRelay)),
+		),
+	)
+}
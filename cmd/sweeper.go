@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"project/config"
+
+	"go.uber.org/fx"
+)
+
+// auditChainBatch is how many queued audit events one chaining transaction
+// moves.
+const auditChainBatch = 500
+
+// HotAccountSweeper moves hot-account bucket credits into balances.
+type HotAccountSweeper interface {
+	SweepHotAccounts(ctx context.Context) (int, error)
+}
+
+// AuditChainer moves audit events queued by transfers into the hash chain.
+type AuditChainer interface {
+	ChainAuditEvents(ctx context.Context, limit int) (int, error)
+}
+
+// RegisterSweeperLifecycle sweeps hot accounts every
+// config.Database.HotAccountSweepInterval until the app stops. A zero
+// interval disables it; balances are correct either way, only the buckets
+// grow.
+func RegisterSweeperLifecycle(lc fx.Lifecycle, sweeper HotAccountSweeper, config *config.Config) {
+	registerTicker(lc, config.Database.HotAccountSweepInterval, "Sweeper", func(ctx context.Context) error {
+		_, err := sweeper.SweepHotAccounts(ctx)
+		return err
+	})
+}
+
+// RegisterAuditChainerLifecycle drains the audit queue into the chain every
+// config.Database.AuditChainInterval until the app stops. A zero interval
+// disables it; queued events wait in audit_pending until a replica with the
+// chainer enabled runs.
+func RegisterAuditChainerLifecycle(lc fx.Lifecycle, chainer AuditChainer, config *config.Config) {
+	registerTicker(lc, config.Database.AuditChainInterval, "AuditChainer", func(ctx context.Context) error {
+		for {
+			n, err := chainer.ChainAuditEvents(ctx, auditChainBatch)
+			if err != nil || n < auditChainBatch {
+				return err
+			}
+		}
+	})
+}
+
+// registerTicker runs fn every interval from start until the app stops, and
+// waits for a running fn on stop. A non-positive interval registers nothing.
+func registerTicker(lc fx.Lifecycle, interval time.Duration, tag string, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+					if err := fn(ctx); err != nil && ctx.Err() == nil {
+						log.Printf("[%s] %v", tag, err)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
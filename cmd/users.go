@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"project/internal/model"
+	"project/internal/service"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func NewUsersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Create, inspect and fund user accounts",
+		Long: `Manages users directly against the database, without a running server.
+Changes are recorded in the audit log with the system account as the actor
+and the operating-system user in the user agent.`,
+	}
+	cmd.AddCommand(
+		newUsersCreateCommand(),
+		newUsersSetPasswordCommand(),
+		newUsersListCommand(),
+		newUsersShowCommand(),
+		newUsersCreditCommand(),
+		newUsersSetHotCommand(),
+		newUsersSeedCommand(),
+	)
+	return cmd
+}
+
+type passwordFlags struct {
+	password string
+	stdin    bool
+}
+
+func (p *passwordFlags) bind(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.password, "password", "", "password (visible in shell history; prefer --password-stdin)")
+	cmd.Flags().BoolVar(&p.stdin, "password-stdin", false, "read the password from the first line of stdin")
+}
+
+func (p *passwordFlags) read(in io.Reader) (string, error) {
+	switch {
+	case p.stdin && p.password != "":
+		return "", fmt.Errorf("--password and --password-stdin are mutually exclusive")
+	case p.stdin:
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	case p.password != "":
+		return p.password, nil
+	}
+	return "", fmt.Errorf("one of --password or --password-stdin is required")
+}
+
+func newUsersCreateCommand() *cobra.Command {
+	var (
+		name     string
+		roles    []string
+		balance  int64
+		password passwordFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pw, err := password.read(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				u, err := svc.CreateUser(ctx, model.CreateUserInput{
+					ActorID:  model.SystemAccountID,
+					Name:     name,
+					Password: pw,
+					Roles:    roles,
+					Balance:  balance,
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), u.ID)
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "display name")
+	cmd.Flags().StringSliceVar(&roles, "roles", nil, "extra roles, e.g. admin (user is always granted)")
+	cmd.Flags().Int64Var(&balance, "balance", 0, "opening balance, credited from the system account")
+	password.bind(cmd)
+	_ = cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func newUsersSetPasswordCommand() *cobra.Command {
+	var password passwordFlags
+	cmd := &cobra.Command{
+		Use:   "set-password <user-id>",
+		Short: "Replace a user's password",
+		Long: `Replaces the stored password hash. Existing sessions stay valid; use the
+admin ForceLogout RPC to end them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			pw, err := password.read(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				return svc.SetPassword(ctx, model.SetPasswordInput{ActorID: model.SystemAccountID, UserID: id, Password: pw})
+			})
+		},
+	}
+	password.bind(cmd)
+	return cmd
+}
+
+func newUsersListCommand() *cobra.Command {
+	var (
+		query string
+		limit int
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users, optionally filtered by name or ID",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				users, err := svc.ListUsers(ctx, query, limit)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tNAME\tBALANCE\tROLES\tSTATUS")
+				for _, u := range users {
+					fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", u.ID, u.Name, u.Balance, u.Roles, u.Status)
+				}
+				return w.Flush()
+			})
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "", "substring of the name, or an exact user ID")
+	cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of users to show (at most 200)")
+	return cmd
+}
+
+func newUsersShowCommand() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "show <user-id>",
+		Short: "Show a user and their recent transactions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				u, txs, err := svc.GetUser(ctx, id, limit)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintf(w, "ID:\t%d\n", u.ID)
+				fmt.Fprintf(w, "Name:\t%s\n", u.Name)
+				fmt.Fprintf(w, "Balance:\t%d\n", u.Balance)
+				fmt.Fprintf(w, "Roles:\t%s\n", u.Roles)
+				fmt.Fprintf(w, "Status:\t%s\n", u.Status)
+				if u.StatusReason != "" {
+					fmt.Fprintf(w, "Status reason:\t%s\n", u.StatusReason)
+				}
+				if u.HotBuckets > 0 {
+					fmt.Fprintf(w, "Hot buckets:\t%d\n", u.HotBuckets)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout())
+				w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "TX\tCREATED AT\tFROM\tTO\tAMOUNT")
+				for _, tx := range txs {
+					fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\n", tx.ID, tx.CreatedAt.UTC().Format("2006-01-02 15:04:05"), tx.From, tx.To, tx.Amount)
+				}
+				return w.Flush()
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "number of recent transactions to show (at most 200)")
+	return cmd
+}
+
+func newUsersCreditCommand() *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "credit <user-id> <amount>",
+		Short: "Credit (or, with a negative amount, debit) a user from the system account",
+		Long: `Moves funds between the system account and the user. Put -- before a
+negative amount so it is not read as a flag: users credit -- 42 -500 --reason ...`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			amount, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid amount %q", args[1])
+			}
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				out, err := svc.Credit(ctx, model.AdjustBalanceInput{
+					ActorID: model.SystemAccountID,
+					UserID:  id,
+					Amount:  amount,
+					Reason:  reason,
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "transaction %d; balance %d\n", out.Transaction.ID, out.Balance)
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the balance is being changed (recorded in the audit log)")
+	_ = cmd.MarkFlagRequired("reason")
+	return cmd
+}
+
+func newUsersSetHotCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-hot <user-id> <buckets>",
+		Short: "Spread credits to a busy account over buckets",
+		Long: fmt.Sprintf(`Puts a merchant or other heavily credited account in hot-account mode:
+credits land in one of <buckets> sub-account buckets chosen at random, so
+concurrent transfers to it do not queue on one row. The server sweeps buckets
+into the balance every HOT_ACCOUNT_SWEEP_INTERVAL; balances and debits always
+see the total. 0 switches the mode off and sweeps at once. At most %d.`, model.MaxHotBuckets),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+			buckets, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid bucket count %q", args[1])
+			}
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				return svc.SetHotBuckets(ctx, model.SetHotBucketsInput{ActorID: model.SystemAccountID, UserID: id, Buckets: buckets})
+			})
+		},
+	}
+}
+
+func newUsersSeedCommand() *cobra.Command {
+	var in model.SeedUsersInput
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Create fixture users and random transfers between them",
+		Long: `Creates --count users, each funded with --balance from the system account and
+sharing --password, then makes --transactions random transfers between them.
+The same --seed produces the same names and transfers.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in.ActorID = model.SystemAccountID
+			return withUserService(cmd, func(ctx context.Context, svc *service.UserService) error {
+				out, err := svc.Seed(ctx, in)
+				if out != nil && len(out.Users) > 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "created users %d-%d and %d transaction(s)\n",
+						out.Users[0].ID, out.Users[len(out.Users)-1].ID, out.Transactions)
+				}
+				return err
+			})
+		},
+	}
+	cmd.Flags().IntVar(&in.Count, "count", 10, "number of users to create")
+	cmd.Flags().Int64Var(&in.Balance, "balance", 10000, "opening balance of each user")
+	cmd.Flags().IntVar(&in.Transactions, "transactions", 50, "number of random transfers to attempt")
+	cmd.Flags().StringVar(&in.Password, "password", "password123", "password shared by every seeded user")
+	cmd.Flags().Int64Var(&in.Seed, "seed", 1, "random seed")
+	return cmd
+}
+
+func parseUserID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid user id %q", s)
+	}
+	return id, nil
+}
+
+// withUserService builds the server's data layer without starting any
+// listeners and runs fn with a context that identifies the CLI caller.
+func withUserService(cmd *cobra.Command, fn func(ctx context.Context, svc *service.UserService) error) error {
+	var svc *service.UserService
+	app := fx.New(
+		fx.NopLogger,
+		dataProviders(),
+		fx.Provide(service.NewUserService),
+		fx.Populate(&svc),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = model.WithRequestInfo(ctx, model.RequestInfo{UserAgent: cliUserAgent(cmd)})
+	return fn(ctx, svc)
+}
+
+func cliUserAgent(cmd *cobra.Command) string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return fmt.Sprintf("%s (os-user=%s)", cmd.CommandPath(), name)
+}
@@ -0,0 +1,204 @@
+package grpcapi
+
+import (
+	"context"
+
+	"project/config"
+	"project/internal/model"
+	pb "project/pkg/pb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AdminService interface {
+	ListUsers(ctx context.Context, in model.ListUsersInput) (*model.ListUsersOutput, error)
+	GetUser(ctx context.Context, in model.GetUserInput) (*model.User, error)
+	ListUserTransactions(ctx context.Context, in model.ListUserTransactionsInput) (*model.ListUserTransactionsOutput, error)
+	AdjustBalance(ctx context.Context, in model.AdjustBalanceInput) (*model.AdjustBalanceOutput, error)
+	FreezeAccount(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error)
+	UnfreezeAccount(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error)
+	SetAccountStatus(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error)
+	ForceLogout(ctx context.Context, in model.ForceLogoutInput) (*model.ForceLogoutOutput, error)
+	ListAuditEvents(ctx context.Context, in model.ListAuditEventsInput) (*model.ListAuditEventsOutput, error)
+}
+
+type Admin struct {
+	pb.UnimplementedAdminServiceServer
+	svc    AdminService
+	config *config.Config
+}
+
+func NewAdmin(svc AdminService, config *config.Config) *Admin {
+	return &Admin{
+		svc:    svc,
+		config: config,
+	}
+}
+
+func (s *Admin) GetUserID(ctx context.Context) int64 {
+	if v, ok := ctx.Value(s.config.UserIDKey).(int64); ok {
+		return v
+	}
+	return 0
+}
+
+func (s *Admin) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	out, err := s.svc.ListUsers(ctx, model.ListUsersInput{
+		ActorID:   s.GetUserID(ctx),
+		Query:     req.Query,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListUsersResponse{NextPageToken: out.NextPageToken}
+	for _, u := range out.Users {
+		resp.Users = append(resp.Users, toPBAdminUser(u))
+	}
+	return resp, nil
+}
+
+func (s *Admin) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.AdminUser, error) {
+	user, err := s.svc.GetUser(ctx, model.GetUserInput{ActorID: s.GetUserID(ctx), UserID: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+	return toPBAdminUser(*user), nil
+}
+
+func (s *Admin) ListUserTransactions(ctx context.Context, req *pb.ListUserTransactionsRequest) (*pb.ListUserTransactionsResponse, error) {
+	out, err := s.svc.ListUserTransactions(ctx, model.ListUserTransactionsInput{
+		ActorID:   s.GetUserID(ctx),
+		UserID:    req.UserId,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListUserTransactionsResponse{NextPageToken: out.NextPageToken}
+	for _, tx := range out.Transactions {
+		resp.Transactions = append(resp.Transactions, &pb.Transaction{
+			Id: int64(tx.ID), From: tx.From, To: tx.To, Amount: tx.Amount,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Admin) AdjustBalance(ctx context.Context, req *pb.AdjustBalanceRequest) (*pb.AdjustBalanceResponse, error) {
+	out, err := s.svc.AdjustBalance(ctx, model.AdjustBalanceInput{
+		ActorID: s.GetUserID(ctx),
+		UserID:  req.UserId,
+		Amount:  req.Amount,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AdjustBalanceResponse{TransactionId: int64(out.Transaction.ID), Balance: out.Balance}, nil
+}
+
+func (s *Admin) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.AdminUser, error) {
+	user, err := s.svc.FreezeAccount(ctx, model.SetAccountStatusInput{
+		ActorID: s.GetUserID(ctx),
+		UserID:  req.UserId,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPBAdminUser(*user), nil
+}
+
+func (s *Admin) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.AdminUser, error) {
+	user, err := s.svc.UnfreezeAccount(ctx, model.SetAccountStatusInput{
+		ActorID: s.GetUserID(ctx),
+		UserID:  req.UserId,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPBAdminUser(*user), nil
+}
+
+func (s *Admin) SetAccountStatus(ctx context.Context, req *pb.SetAccountStatusRequest) (*pb.AdminUser, error) {
+	user, err := s.svc.SetAccountStatus(ctx, model.SetAccountStatusInput{
+		ActorID: s.GetUserID(ctx),
+		UserID:  req.UserId,
+		Status:  req.Status,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPBAdminUser(*user), nil
+}
+
+func (s *Admin) ForceLogout(ctx context.Context, req *pb.ForceLogoutRequest) (*pb.ForceLogoutResponse, error) {
+	out, err := s.svc.ForceLogout(ctx, model.ForceLogoutInput{
+		ActorID: s.GetUserID(ctx),
+		UserID:  req.UserId,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ForceLogoutResponse{Success: out.Success}, nil
+}
+
+func (s *Admin) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	in := model.ListAuditEventsInput{
+		ActorID: s.GetUserID(ctx),
+		Filter: model.AuditFilter{
+			ActorID:      req.ActorId,
+			TargetUserID: req.TargetUserId,
+			Action:       req.Action,
+		},
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	if req.Since != nil {
+		in.Filter.Since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		in.Filter.Until = req.Until.AsTime()
+	}
+	out, err := s.svc.ListAuditEvents(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListAuditEventsResponse{NextPageToken: out.NextPageToken}
+	for _, e := range out.Events {
+		resp.Events = append(resp.Events, &pb.AuditEvent{
+			Id:           e.ID,
+			ActorId:      e.ActorID,
+			Action:       e.Action,
+			TargetUserId: e.TargetUserID,
+			Reason:       e.Reason,
+			Details:      e.Details,
+			Before:       e.Before,
+			After:        e.After,
+			Ip:           e.IP,
+			UserAgent:    e.UserAgent,
+			RequestId:    e.RequestID,
+			CreatedAt:    timestamppb.New(e.CreatedAt),
+			PrevHash:     e.PrevHash,
+			Hash:         e.Hash,
+		})
+	}
+	return resp, nil
+}
+
+func toPBAdminUser(u model.User) *pb.AdminUser {
+	return &pb.AdminUser{
+		Id:              u.ID,
+		Name:            u.Name,
+		Balance:         u.Balance,
+		Roles:           u.RoleList(),
+		Status:          u.Status,
+		StatusReason:    u.StatusReason,
+		StatusChangedAt: optionalTimestamp(u.StatusChangedAt),
+	}
+}
@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	pb "project/pkg/pb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, in model.CreateAPIKeyInput) (*model.CreateAPIKeyOutput, error)
+	ListAPIKeys(ctx context.Context, in model.ListAPIKeysInput) (*model.ListAPIKeysOutput, error)
+	RevokeAPIKey(ctx context.Context, in model.RevokeAPIKeyInput) (*model.RevokeAPIKeyOutput, error)
+}
+
+type APIKeys struct {
+	pb.UnimplementedAPIKeyServiceServer
+	svc    APIKeyService
+	config *config.Config
+}
+
+func NewAPIKeys(svc APIKeyService, config *config.Config) *APIKeys {
+	return &APIKeys{
+		svc:    svc,
+		config: config,
+	}
+}
+
+func (s *APIKeys) GetUserID(ctx context.Context) int64 {
+	if v, ok := ctx.Value(s.config.UserIDKey).(int64); ok {
+		return v
+	}
+	return 0
+}
+
+func (s *APIKeys) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	in := model.CreateAPIKeyInput{
+		OwnerID:        s.GetUserID(ctx),
+		ServiceAccount: req.ServiceAccount,
+		Scopes:         req.Scopes,
+		TTL:            time.Duration(req.TtlSeconds) * time.Second,
+	}
+	out, err := s.svc.CreateAPIKey(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateAPIKeyResponse{Key: toPBAPIKey(out.Key), Secret: out.Secret}, nil
+}
+
+func (s *APIKeys) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	out, err := s.svc.ListAPIKeys(ctx, model.ListAPIKeysInput{OwnerID: s.GetUserID(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListAPIKeysResponse{}
+	for _, k := range out.Keys {
+		resp.Keys = append(resp.Keys, toPBAPIKey(k))
+	}
+	return resp, nil
+}
+
+func (s *APIKeys) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	out, err := s.svc.RevokeAPIKey(ctx, model.RevokeAPIKeyInput{OwnerID: s.GetUserID(ctx), ID: req.Id})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RevokeAPIKeyResponse{Success: out.Success}, nil
+}
+
+func toPBAPIKey(k model.APIKeyRecord) *pb.APIKey {
+	return &pb.APIKey{
+		Id:             k.ID,
+		ServiceAccount: k.ServiceAccountName,
+		Prefix:         "tk_" + k.Prefix,
+		Scopes:         k.ScopeList(),
+		CreatedAt:      timestamppb.New(k.CreatedAt),
+		ExpiresAt:      optionalTimestamp(k.ExpiresAt),
+		LastUsedAt:     optionalTimestamp(k.LastUsedAt),
+		RevokedAt:      optionalTimestamp(k.RevokedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
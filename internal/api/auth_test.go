@@ -0,0 +1,78 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+	"project/internal/utils"
+	pb "project/pkg/pb"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuth_LoginLogout(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	signer, err := utils.GenerateEd25519Key()
+	require.NoError(t, err)
+	keys, err := utils.NewKeySet("test", "test", signer)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		req     *pb.LoginRequest
+		wantErr error
+		roles   []string
+	}{
+		{name: "user", req: &pb.LoginRequest{Username: 2, Password: "password123"}, roles: []string{model.RoleUser}},
+		{name: "admin", req: &pb.LoginRequest{Username: 1, Password: "password123"}, roles: []string{model.RoleUser, model.RoleAdmin}},
+		{name: "bad password", req: &pb.LoginRequest{Username: 1, Password: "nope"}, wantErr: service.ErrInvalidCredentials},
+		{name: "unknown user", req: &pb.LoginRequest{Username: 9, Password: "password123"}, wantErr: service.ErrInvalidCredentials},
+		{name: "closed account", req: &pb.LoginRequest{Username: 3, Password: "password123"}, wantErr: service.ErrAccountClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repotest.NewTransferRepo(t,
+				model.User{ID: 1, Name: "alice", Password: string(hash), Roles: "user admin"},
+				model.User{ID: 2, Name: "bob", Password: string(hash)},
+				model.User{ID: 3, Name: "carol", Password: string(hash), Status: model.StatusClosed},
+			)
+			tokens := repo.NewMemoryTokenStore()
+			h, cfg := newTestAuth(store, tokens, keys)
+
+			resp, err := h.Login(context.Background(), tt.req)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			claims, err := keys.ValidateAccessToken(resp.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, tt.req.Username, claims.UserID)
+			require.Equal(t, tt.roles, claims.Roles)
+			stored, err := tokens.GetToken(context.Background(), tt.req.Username)
+			require.NoError(t, err)
+			require.Equal(t, resp.AccessToken, stored)
+
+			out, err := h.Logout(asUser(cfg, tt.req.Username), &pb.LogoutRequest{})
+			require.NoError(t, err)
+			require.True(t, out.Success)
+			stored, err = tokens.GetToken(context.Background(), tt.req.Username)
+			require.NoError(t, err)
+			require.Empty(t, stored)
+		})
+	}
+}
+
+func newTestAuth(store *repo.MemoryTransferRepo, tokens *repo.MemoryTokenStore, keys *utils.KeySet) (*Auth, *config.Config) {
+	cfg := config.LoadConfig()
+	return NewAuth(service.NewAuthService(cfg, store, tokens, keys, store), cfg), cfg
+}
@@ -2,33 +2,30 @@ package grpcapi
 
 import (
 	"context"
-	"fmt"
 	"project/config"
 	"project/internal/model"
 	pb "project/pkg/pb"
 )
 
-type Publisher interface {
-	Publish(data []byte) error
-}
-
 type TransferService interface {
 	ListTransactions(ctx context.Context, in model.ListTransactionsInput) (*model.ListTransactionsOutput, error)
 	InsertTransaction(ctx context.Context, in model.SendMoneyInput) (*model.SendMoneyOutput, error)
+	SendMoneyBatch(ctx context.Context, in model.SendMoneyBatchInput) (*model.SendMoneyBatchOutput, error)
 	GetBalance(ctx context.Context, in model.GetBalanceInput) (*model.GetBalanceOutput, error)
 }
 
+// Transfer serves TransferService. Events for completed transfers are not
+// published here: the repository writes them to the outbox in the transfer's
+// own transaction, and the event relay publishes them.
 type Transfer struct {
 	pb.UnimplementedTransferServiceServer
 	svc    TransferService
-	pubsub Publisher
 	config *config.Config
 }
 
-func NewTransferService(svc TransferService, pubsub Publisher, config *config.Config) *Transfer {
+func NewTransferService(svc TransferService, config *config.Config) *Transfer {
 	return &Transfer{
 		svc:    svc,
-		pubsub: pubsub,
 		config: config,
 	}
 }
@@ -47,16 +44,29 @@ func (s *Transfer) SendMoney(ctx context.Context, req *pb.SendMoneyRequest) (*pb
 	}
 	out, err := s.svc.InsertTransaction(ctx, in)
 	if err != nil {
-		return &pb.SendMoneyResponse{Success: out.Success, ErrorMessage: out.ErrorMessage}, err
+		return nil, err
 	}
-	msg := fmt.Sprintf(
-		`{"from":"%d","to":"%d","amount":%d,"status":"success"}`,
-		userId, req.To, req.Amount,
-	)
-	if err := s.pubsub.Publish([]byte(msg)); err != nil {
-		fmt.Printf("[WARN] publish failed: %v\n", err)
+	return &pb.SendMoneyResponse{Success: out.Success, TransactionId: int64(out.Transaction.ID)}, nil
+}
+
+func (s *Transfer) SendMoneyBatch(ctx context.Context, req *pb.SendMoneyBatchRequest) (*pb.SendMoneyBatchResponse, error) {
+	userId := s.GetUserID(ctx)
+	in := model.SendMoneyBatchInput{From: userId}
+	for _, l := range req.Lines {
+		in.Lines = append(in.Lines, model.TransferLine{To: l.To, Amount: l.Amount})
 	}
-	return &pb.SendMoneyResponse{Success: out.Success, ErrorMessage: out.ErrorMessage}, nil
+	out, err := s.svc.SendMoneyBatch(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.SendMoneyBatchResponse{BatchId: out.BatchID, Total: out.Total}
+	for i, tx := range out.Transactions {
+		resp.Results = append(resp.Results, &pb.TransferLineResult{
+			Line: int32(i), To: tx.To, Amount: tx.Amount, TransactionId: int64(tx.ID),
+		})
+	}
+	return resp, nil
 }
 
 func (s *Transfer) ListTransactions(ctx context.Context, req *pb.ListTransactionsRequest) (*pb.ListTransactionsResponse, error) {
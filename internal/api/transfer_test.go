@@ -0,0 +1,218 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+	pb "project/pkg/pb"
+
+	"github.com/stretchr/testify/require"
+)
+
+// relayed drains the store's outbox and returns the ordering keys and events
+// published, in order.
+func relayed(t *testing.T, store *repo.MemoryTransferRepo) ([]string, []model.Event) {
+	t.Helper()
+	var keys []string
+	var events []model.Event
+	_, err := store.RelayEvents(context.Background(), 1000, func(msgs []repo.Message) error {
+		for _, m := range msgs {
+			event, err := model.DecodeEvent(m.Data)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, m.OrderingKey)
+			events = append(events, event)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return keys, events
+}
+
+func asUser(cfg *config.Config, userID int64) context.Context {
+	return context.WithValue(context.Background(), cfg.UserIDKey, userID)
+}
+
+func newTestTransfer(t *testing.T) (*Transfer, *repo.MemoryTransferRepo, *config.Config) {
+	cfg := config.LoadConfig()
+	store := repotest.NewTransferRepo(t,
+		model.User{ID: 1, Name: "alice", Balance: 100},
+		model.User{ID: 2, Name: "bob", Balance: 50},
+		model.User{ID: 3, Name: "carol", Balance: 50, Status: model.StatusFrozen},
+	)
+	return NewTransferService(service.NewTransferService(store, cfg), cfg), store, cfg
+}
+
+func TestTransfer_SendMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		req      *pb.SendMoneyRequest
+		wantErr  error
+		balances map[int64]int64
+	}{
+		{name: "ok", userID: 1, req: &pb.SendMoneyRequest{To: 2, Amount: 30}, balances: map[int64]int64{1: 70, 2: 80}},
+		{name: "no caller", userID: 0, req: &pb.SendMoneyRequest{To: 2, Amount: 1}, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "insufficient funds", userID: 2, req: &pb.SendMoneyRequest{To: 1, Amount: 51}, wantErr: service.ErrInsufficientFunds},
+		{name: "self transfer", userID: 1, req: &pb.SendMoneyRequest{To: 1, Amount: 1}, wantErr: service.ErrSelfTransfer},
+		{name: "frozen recipient", userID: 1, req: &pb.SendMoneyRequest{To: 3, Amount: 1}, wantErr: service.ErrRecipientFrozen},
+		{name: "unknown recipient", userID: 1, req: &pb.SendMoneyRequest{To: 9, Amount: 1}, wantErr: service.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store, cfg := newTestTransfer(t)
+			ctx := asUser(cfg, tt.userID)
+
+			resp, err := h.SendMoney(ctx, tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				_, events := relayed(t, store)
+				require.Empty(t, events)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, resp.Success)
+			require.NotZero(t, resp.TransactionId)
+			for id, want := range tt.balances {
+				got, err := store.GetBalance(ctx, id)
+				require.NoError(t, err)
+				require.Equal(t, want, got, "balance of user %d", id)
+			}
+
+			// One event on the sender's stream and one on the recipient's.
+			keys, events := relayed(t, store)
+			require.Equal(t, []string{model.AccountOrderingKey(tt.userID), model.AccountOrderingKey(tt.req.To)}, keys)
+			want := model.TransferCompleted{
+				TransactionID: uint(resp.TransactionId), From: tt.userID, To: tt.req.To, Amount: tt.req.Amount,
+			}
+			for i, account := range []int64{tt.userID, tt.req.To} {
+				require.Equal(t, model.EventTransferCompleted, events[i].Type)
+				require.Equal(t, account, events[i].Account)
+				var data model.TransferCompleted
+				require.NoError(t, json.Unmarshal(events[i].Data, &data))
+				require.Equal(t, want, data)
+			}
+			require.NotEqual(t, events[0].ID, events[1].ID)
+		})
+	}
+}
+
+func TestTransfer_PublishFailureKeepsEvents(t *testing.T) {
+	h, store, cfg := newTestTransfer(t)
+	_, err := h.SendMoney(asUser(cfg, 2), &pb.SendMoneyRequest{To: 1, Amount: 50})
+	require.NoError(t, err, "a broker outage does not fail the transfer")
+
+	n, err := store.RelayEvents(context.Background(), 1000, func([]repo.Message) error { return errors.New("broker down") })
+	require.Error(t, err)
+	require.Zero(t, n)
+
+	_, err = h.SendMoney(asUser(cfg, 1), &pb.SendMoneyRequest{To: 2, Amount: 10})
+	require.NoError(t, err)
+	keys, events := relayed(t, store)
+	require.Len(t, events, 4)
+	require.Equal(t, []string{
+		model.AccountOrderingKey(2), model.AccountOrderingKey(1),
+		model.AccountOrderingKey(1), model.AccountOrderingKey(2),
+	}, keys, "events held back by the failure are published first")
+}
+
+func TestTransfer_SendMoneyBatch(t *testing.T) {
+	h, store, cfg := newTestTransfer(t)
+	ctx := asUser(cfg, 1)
+
+	// A failing line rolls back the whole batch and publishes nothing.
+	_, err := h.SendMoneyBatch(ctx, &pb.SendMoneyBatchRequest{Lines: []*pb.TransferLine{{To: 2, Amount: 10}, {To: 3, Amount: 10}}})
+	require.ErrorIs(t, err, service.ErrRecipientFrozen)
+	e, _ := service.AsError(err)
+	require.Equal(t, "1", e.Metadata["line"])
+	_, events := relayed(t, store)
+	require.Empty(t, events)
+	balance, err := store.GetBalance(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, balance)
+
+	resp, err := h.SendMoneyBatch(ctx, &pb.SendMoneyBatchRequest{Lines: []*pb.TransferLine{{To: 2, Amount: 10}, {To: 2, Amount: 15}}})
+	require.NoError(t, err)
+	require.EqualValues(t, 25, resp.Total)
+	require.Len(t, resp.Results, 2)
+	for i, r := range resp.Results {
+		require.EqualValues(t, i, r.Line)
+		require.EqualValues(t, 2, r.To)
+		require.NotZero(t, r.TransactionId)
+	}
+	balance, err = store.GetBalance(ctx, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 75, balance)
+
+	// Each line on both streams, then the summary on the sender's.
+	keys, events := relayed(t, store)
+	sender, recipient := model.AccountOrderingKey(1), model.AccountOrderingKey(2)
+	require.Equal(t, []string{sender, recipient, sender, recipient, sender}, keys)
+	for _, event := range events[:4] {
+		require.Equal(t, model.EventTransferCompleted, event.Type)
+	}
+	summary := events[4]
+	require.Equal(t, model.EventBatchTransferCompleted, summary.Type)
+	require.Equal(t, resp.BatchId, summary.ID)
+	var data model.BatchTransferCompleted
+	require.NoError(t, json.Unmarshal(summary.Data, &data))
+	require.Equal(t, model.BatchTransferCompleted{
+		BatchID: resp.BatchId, From: 1, Lines: 2, Total: 25,
+		TransactionIDs: []uint{uint(resp.Results[0].TransactionId), uint(resp.Results[1].TransactionId)},
+	}, data)
+}
+
+func TestTransfer_GetBalanceAndListTransactions(t *testing.T) {
+	h, _, cfg := newTestTransfer(t)
+	_, err := h.SendMoney(asUser(cfg, 1), &pb.SendMoneyRequest{To: 2, Amount: 10})
+	require.NoError(t, err)
+	_, err = h.SendMoney(asUser(cfg, 1), &pb.SendMoneyRequest{To: 2, Amount: 5})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		userID  int64
+		balance int64
+		sent    []int64
+		wantErr error
+	}{
+		{name: "sender", userID: 1, balance: 85, sent: []int64{10, 5}},
+		{name: "recipient", userID: 2, balance: 65},
+		{name: "frozen", userID: 3, balance: 50},
+		{name: "no caller", userID: 0, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := asUser(cfg, tt.userID)
+
+			balance, err := h.GetBalance(ctx, &pb.GetBalanceRequest{})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				_, err = h.ListTransactions(ctx, &pb.ListTransactionsRequest{})
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.userID, balance.UserId)
+			require.Equal(t, tt.balance, balance.Balance)
+
+			list, err := h.ListTransactions(ctx, &pb.ListTransactionsRequest{})
+			require.NoError(t, err)
+			require.EqualValues(t, len(tt.sent), list.Number)
+			var sent []int64
+			for _, tx := range list.Transactions {
+				require.Equal(t, tt.userID, tx.From)
+				sent = append(sent, tx.Amount)
+			}
+			require.Equal(t, tt.sent, sent)
+		})
+	}
+}
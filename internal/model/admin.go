@@ -0,0 +1,108 @@
+package model
+
+type ListUsersInput struct {
+	ActorID   int64
+	Query     string
+	PageSize  int
+	PageToken string
+}
+
+type ListUsersOutput struct {
+	Users         []User
+	NextPageToken string
+}
+
+type GetUserInput struct {
+	ActorID int64
+	UserID  int64
+}
+
+type ListUserTransactionsInput struct {
+	ActorID   int64
+	UserID    int64
+	PageSize  int
+	PageToken string
+}
+
+type ListUserTransactionsOutput struct {
+	Transactions  []Transaction
+	NextPageToken string
+}
+
+type AdjustBalanceInput struct {
+	ActorID int64
+	UserID  int64
+	Amount  int64
+	Reason  string
+}
+
+type AdjustBalanceOutput struct {
+	Transaction Transaction
+	Balance     int64
+}
+
+type SetAccountStatusInput struct {
+	ActorID int64
+	UserID  int64
+	Status  string
+	Reason  string
+}
+
+type ForceLogoutInput struct {
+	ActorID int64
+	UserID  int64
+	Reason  string
+}
+
+type ForceLogoutOutput struct {
+	Success bool
+}
+
+type ListAuditEventsInput struct {
+	ActorID   int64
+	Filter    AuditFilter
+	PageSize  int
+	PageToken string
+}
+
+type ListAuditEventsOutput struct {
+	Events        []AuditEvent
+	NextPageToken string
+}
+
+type CreateUserInput struct {
+	ActorID  int64
+	Name     string
+	Password string
+	Roles    []string
+	// Balance is an opening credit drawn from the system account.
+	Balance int64
+}
+
+type SetPasswordInput struct {
+	ActorID  int64
+	UserID   int64
+	Password string
+}
+
+// SetHotBucketsInput turns hot-account mode on (Buckets > 0) or off.
+type SetHotBucketsInput struct {
+	ActorID int64
+	UserID  int64
+	Buckets int
+}
+
+type SeedUsersInput struct {
+	ActorID      int64
+	Count        int
+	Balance      int64
+	Transactions int
+	Password     string
+	// Seed makes the generated names, balances and transfers repeatable.
+	Seed int64
+}
+
+type SeedUsersOutput struct {
+	Users        []User
+	Transactions int
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+//go:generate goqueryset -in apikey.go
+
+// ServiceAccount is a non-human principal owned by a user. Requests made with
+// one of its API keys act as OwnerID, limited to the key's scopes.
+// gen:qs
+type ServiceAccount struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	OwnerID   int64     `gorm:"not null;uniqueIndex:idx_service_account_owner_name"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_service_account_owner_name"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// APIKey stores only a SHA-256 hash of the key secret. Prefix is the public
+// part of the key used to look it up.
+// gen:qs
+type APIKey struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	ServiceAccountID int64     `gorm:"not null;index"`
+	Prefix           string    `gorm:"not null;uniqueIndex"`
+	SecretHash       string    `gorm:"not null"`
+	Scopes           string    `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	ExpiresAt        *time.Time
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time
+}
+
+// Scopes an API key can grant.
+const (
+	ScopeTransferSend = "transfer:send"
+	ScopeTransferRead = "transfer:read"
+)
+
+var KnownScopes = []string{ScopeTransferSend, ScopeTransferRead}
+
+// APIKeyRecord is an API key joined with its service account.
+type APIKeyRecord struct {
+	APIKey             `gorm:"embedded"`
+	ServiceAccountName string
+	OwnerID            int64
+}
+
+func (r APIKeyRecord) ScopeList() []string {
+	return strings.Fields(r.Scopes)
+}
+
+// APIKeyPrincipal is who a request authenticated with an API key acts as.
+type APIKeyPrincipal struct {
+	UserID           int64
+	ServiceAccountID int64
+	KeyID            int64
+	Scopes           []string
+}
+
+func (p APIKeyPrincipal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateAPIKeyInput struct {
+	OwnerID        int64
+	ServiceAccount string
+	Scopes         []string
+	TTL            time.Duration
+}
+
+type CreateAPIKeyOutput struct {
+	Key    APIKeyRecord
+	Secret string
+}
+
+type ListAPIKeysInput struct {
+	OwnerID int64
+}
+
+type ListAPIKeysOutput struct {
+	Keys []APIKeyRecord
+}
+
+type RevokeAPIKeyInput struct {
+	OwnerID int64
+	ID      int64
+}
+
+type RevokeAPIKeyOutput struct {
+	Success bool
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -120,6 +121,42 @@ func (qs TransactionQuerySet) Count() (int64, error) {
 	return count, err
 }
 
+// CreatedAtEq is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtEq(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at = ?", createdAt))
+}
+
+// CreatedAtGt is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtGt(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at > ?", createdAt))
+}
+
+// CreatedAtGte is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtGte(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at >= ?", createdAt))
+}
+
+// CreatedAtLt is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtLt(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at < ?", createdAt))
+}
+
+// CreatedAtLte is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtLte(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at <= ?", createdAt))
+}
+
+// CreatedAtNe is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) CreatedAtNe(createdAt time.Time) TransactionQuerySet {
+	return qs.w(qs.db.Where("created_at != ?", createdAt))
+}
+
 // Delete is an autogenerated method
 // nolint: dupl
 func (qs TransactionQuerySet) Delete() error {
@@ -288,6 +325,12 @@ func (qs TransactionQuerySet) OrderAscByAmount() TransactionQuerySet {
 	return qs.w(qs.db.Order("amount ASC"))
 }
 
+// OrderAscByCreatedAt is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) OrderAscByCreatedAt() TransactionQuerySet {
+	return qs.w(qs.db.Order("created_at ASC"))
+}
+
 // OrderAscByFrom is an autogenerated method
 // nolint: dupl
 func (qs TransactionQuerySet) OrderAscByFrom() TransactionQuerySet {
@@ -312,6 +355,12 @@ func (qs TransactionQuerySet) OrderDescByAmount() TransactionQuerySet {
 	return qs.w(qs.db.Order("amount DESC"))
 }
 
+// OrderDescByCreatedAt is an autogenerated method
+// nolint: dupl
+func (qs TransactionQuerySet) OrderDescByCreatedAt() TransactionQuerySet {
+	return qs.w(qs.db.Order("created_at DESC"))
+}
+
 // OrderDescByFrom is an autogenerated method
 // nolint: dupl
 func (qs TransactionQuerySet) OrderDescByFrom() TransactionQuerySet {
@@ -393,6 +442,13 @@ func (u TransactionUpdater) SetAmount(amount int64) TransactionUpdater {
 	return u
 }
 
+// SetCreatedAt is an autogenerated method
+// nolint: dupl
+func (u TransactionUpdater) SetCreatedAt(createdAt time.Time) TransactionUpdater {
+	u.fields[string(TransactionDBSchema.CreatedAt)] = createdAt
+	return u
+}
+
 // SetFrom is an autogenerated method
 // nolint: dupl
 func (u TransactionUpdater) SetFrom(from int64) TransactionUpdater {
@@ -442,26 +498,29 @@ func (f TransactionDBSchemaField) String() string {
 
 // TransactionDBSchema stores db field names of Transaction
 var TransactionDBSchema = struct {
-	ID     TransactionDBSchemaField
-	From   TransactionDBSchemaField
-	To     TransactionDBSchemaField
-	Amount TransactionDBSchemaField
+	ID        TransactionDBSchemaField
+	From      TransactionDBSchemaField
+	To        TransactionDBSchemaField
+	Amount    TransactionDBSchemaField
+	CreatedAt TransactionDBSchemaField
 }{
 
-	ID:     TransactionDBSchemaField("id"),
-	From:   TransactionDBSchemaField("from_user"),
-	To:     TransactionDBSchemaField("to_user"),
-	Amount: TransactionDBSchemaField("amount"),
+	ID:        TransactionDBSchemaField("id"),
+	From:      TransactionDBSchemaField("from_user"),
+	To:        TransactionDBSchemaField("to_user"),
+	Amount:    TransactionDBSchemaField("amount"),
+	CreatedAt: TransactionDBSchemaField("created_at"),
 }
 
 // Update updates Transaction fields by primary key
 // nolint: dupl
 func (o *Transaction) Update(db *gorm.DB, fields ...TransactionDBSchemaField) error {
 	dbNameToFieldName := map[string]interface{}{
-		"id":        o.ID,
-		"from_user": o.From,
-		"to_user":   o.To,
-		"amount":    o.Amount,
+		"id":         o.ID,
+		"from_user":  o.From,
+		"to_user":    o.To,
+		"amount":     o.Amount,
+		"created_at": o.CreatedAt,
 	}
 	u := map[string]interface{}{}
 	for _, f := range fields {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -152,6 +153,62 @@ func (qs UserQuerySet) GetUpdater() UserUpdater {
 	return NewUserUpdater(qs.db)
 }
 
+// HotBucketsEq is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsEq(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets = ?", hotBuckets))
+}
+
+// HotBucketsGt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsGt(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets > ?", hotBuckets))
+}
+
+// HotBucketsGte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsGte(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets >= ?", hotBuckets))
+}
+
+// HotBucketsIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsIn(hotBuckets ...int) UserQuerySet {
+	if len(hotBuckets) == 0 {
+		qs.db.AddError(errors.New("must at least pass one hotBuckets in HotBucketsIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("hot_buckets IN (?)", hotBuckets))
+}
+
+// HotBucketsLt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsLt(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets < ?", hotBuckets))
+}
+
+// HotBucketsLte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsLte(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets <= ?", hotBuckets))
+}
+
+// HotBucketsNe is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsNe(hotBuckets int) UserQuerySet {
+	return qs.w(qs.db.Where("hot_buckets != ?", hotBuckets))
+}
+
+// HotBucketsNotIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) HotBucketsNotIn(hotBuckets ...int) UserQuerySet {
+	if len(hotBuckets) == 0 {
+		qs.db.AddError(errors.New("must at least pass one hotBuckets in HotBucketsNotIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("hot_buckets NOT IN (?)", hotBuckets))
+}
+
 // IDEq is an autogenerated method
 // nolint: dupl
 func (qs UserQuerySet) IDEq(ID int64) UserQuerySet {
@@ -300,6 +357,12 @@ func (qs UserQuerySet) OrderAscByBalance() UserQuerySet {
 	return qs.w(qs.db.Order("balance ASC"))
 }
 
+// OrderAscByHotBuckets is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderAscByHotBuckets() UserQuerySet {
+	return qs.w(qs.db.Order("hot_buckets ASC"))
+}
+
 // OrderAscByID is an autogenerated method
 // nolint: dupl
 func (qs UserQuerySet) OrderAscByID() UserQuerySet {
@@ -318,12 +381,36 @@ func (qs UserQuerySet) OrderAscByPassword() UserQuerySet {
 	return qs.w(qs.db.Order("password ASC"))
 }
 
+// OrderAscByRoles is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderAscByRoles() UserQuerySet {
+	return qs.w(qs.db.Order("roles ASC"))
+}
+
+// OrderAscByStatus is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderAscByStatus() UserQuerySet {
+	return qs.w(qs.db.Order("status ASC"))
+}
+
+// OrderAscByStatusReason is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderAscByStatusReason() UserQuerySet {
+	return qs.w(qs.db.Order("status_reason ASC"))
+}
+
 // OrderDescByBalance is an autogenerated method
 // nolint: dupl
 func (qs UserQuerySet) OrderDescByBalance() UserQuerySet {
 	return qs.w(qs.db.Order("balance DESC"))
 }
 
+// OrderDescByHotBuckets is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderDescByHotBuckets() UserQuerySet {
+	return qs.w(qs.db.Order("hot_buckets DESC"))
+}
+
 // OrderDescByID is an autogenerated method
 // nolint: dupl
 func (qs UserQuerySet) OrderDescByID() UserQuerySet {
@@ -342,6 +429,24 @@ func (qs UserQuerySet) OrderDescByPassword() UserQuerySet {
 	return qs.w(qs.db.Order("password DESC"))
 }
 
+// OrderDescByRoles is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderDescByRoles() UserQuerySet {
+	return qs.w(qs.db.Order("roles DESC"))
+}
+
+// OrderDescByStatus is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderDescByStatus() UserQuerySet {
+	return qs.w(qs.db.Order("status DESC"))
+}
+
+// OrderDescByStatusReason is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) OrderDescByStatusReason() UserQuerySet {
+	return qs.w(qs.db.Order("status_reason DESC"))
+}
+
 // PasswordEq is an autogenerated method
 // nolint: dupl
 func (qs UserQuerySet) PasswordEq(password string) UserQuerySet {
@@ -410,6 +515,222 @@ func (qs UserQuerySet) PasswordNotlike(password string) UserQuerySet {
 	return qs.w(qs.db.Where("password NOT LIKE ?", password))
 }
 
+// RolesEq is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesEq(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles = ?", roles))
+}
+
+// RolesGt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesGt(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles > ?", roles))
+}
+
+// RolesGte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesGte(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles >= ?", roles))
+}
+
+// RolesIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesIn(roles ...string) UserQuerySet {
+	if len(roles) == 0 {
+		qs.db.AddError(errors.New("must at least pass one roles in RolesIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("roles IN (?)", roles))
+}
+
+// RolesLike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesLike(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles LIKE ?", roles))
+}
+
+// RolesLt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesLt(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles < ?", roles))
+}
+
+// RolesLte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesLte(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles <= ?", roles))
+}
+
+// RolesNe is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesNe(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles != ?", roles))
+}
+
+// RolesNotIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesNotIn(roles ...string) UserQuerySet {
+	if len(roles) == 0 {
+		qs.db.AddError(errors.New("must at least pass one roles in RolesNotIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("roles NOT IN (?)", roles))
+}
+
+// RolesNotlike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) RolesNotlike(roles string) UserQuerySet {
+	return qs.w(qs.db.Where("roles NOT LIKE ?", roles))
+}
+
+// StatusChangedAtIsNotNull is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusChangedAtIsNotNull() UserQuerySet {
+	return qs.w(qs.db.Where("status_changed_at IS NOT NULL"))
+}
+
+// StatusChangedAtIsNull is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusChangedAtIsNull() UserQuerySet {
+	return qs.w(qs.db.Where("status_changed_at IS NULL"))
+}
+
+// StatusEq is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusEq(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status = ?", status))
+}
+
+// StatusGt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusGt(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status > ?", status))
+}
+
+// StatusGte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusGte(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status >= ?", status))
+}
+
+// StatusIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusIn(status ...string) UserQuerySet {
+	if len(status) == 0 {
+		qs.db.AddError(errors.New("must at least pass one status in StatusIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("status IN (?)", status))
+}
+
+// StatusLike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusLike(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status LIKE ?", status))
+}
+
+// StatusLt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusLt(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status < ?", status))
+}
+
+// StatusLte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusLte(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status <= ?", status))
+}
+
+// StatusNe is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusNe(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status != ?", status))
+}
+
+// StatusNotIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusNotIn(status ...string) UserQuerySet {
+	if len(status) == 0 {
+		qs.db.AddError(errors.New("must at least pass one status in StatusNotIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("status NOT IN (?)", status))
+}
+
+// StatusNotlike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusNotlike(status string) UserQuerySet {
+	return qs.w(qs.db.Where("status NOT LIKE ?", status))
+}
+
+// StatusReasonEq is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonEq(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason = ?", statusReason))
+}
+
+// StatusReasonGt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonGt(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason > ?", statusReason))
+}
+
+// StatusReasonGte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonGte(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason >= ?", statusReason))
+}
+
+// StatusReasonIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonIn(statusReason ...string) UserQuerySet {
+	if len(statusReason) == 0 {
+		qs.db.AddError(errors.New("must at least pass one statusReason in StatusReasonIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("status_reason IN (?)", statusReason))
+}
+
+// StatusReasonLike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonLike(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason LIKE ?", statusReason))
+}
+
+// StatusReasonLt is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonLt(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason < ?", statusReason))
+}
+
+// StatusReasonLte is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonLte(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason <= ?", statusReason))
+}
+
+// StatusReasonNe is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonNe(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason != ?", statusReason))
+}
+
+// StatusReasonNotIn is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonNotIn(statusReason ...string) UserQuerySet {
+	if len(statusReason) == 0 {
+		qs.db.AddError(errors.New("must at least pass one statusReason in StatusReasonNotIn"))
+		return qs.w(qs.db)
+	}
+	return qs.w(qs.db.Where("status_reason NOT IN (?)", statusReason))
+}
+
+// StatusReasonNotlike is an autogenerated method
+// nolint: dupl
+func (qs UserQuerySet) StatusReasonNotlike(statusReason string) UserQuerySet {
+	return qs.w(qs.db.Where("status_reason NOT LIKE ?", statusReason))
+}
+
 // SetBalance is an autogenerated method
 // nolint: dupl
 func (u UserUpdater) SetBalance(balance int64) UserUpdater {
@@ -417,6 +738,13 @@ func (u UserUpdater) SetBalance(balance int64) UserUpdater {
 	return u
 }
 
+// SetHotBuckets is an autogenerated method
+// nolint: dupl
+func (u UserUpdater) SetHotBuckets(hotBuckets int) UserUpdater {
+	u.fields[string(UserDBSchema.HotBuckets)] = hotBuckets
+	return u
+}
+
 // SetID is an autogenerated method
 // nolint: dupl
 func (u UserUpdater) SetID(ID int64) UserUpdater {
@@ -438,6 +766,34 @@ func (u UserUpdater) SetPassword(password string) UserUpdater {
 	return u
 }
 
+// SetRoles is an autogenerated method
+// nolint: dupl
+func (u UserUpdater) SetRoles(roles string) UserUpdater {
+	u.fields[string(UserDBSchema.Roles)] = roles
+	return u
+}
+
+// SetStatus is an autogenerated method
+// nolint: dupl
+func (u UserUpdater) SetStatus(status string) UserUpdater {
+	u.fields[string(UserDBSchema.Status)] = status
+	return u
+}
+
+// SetStatusChangedAt is an autogenerated method
+// nolint: dupl
+func (u UserUpdater) SetStatusChangedAt(statusChangedAt *time.Time) UserUpdater {
+	u.fields[string(UserDBSchema.StatusChangedAt)] = statusChangedAt
+	return u
+}
+
+// SetStatusReason is an autogenerated method
+// nolint: dupl
+func (u UserUpdater) SetStatusReason(statusReason string) UserUpdater {
+	u.fields[string(UserDBSchema.StatusReason)] = statusReason
+	return u
+}
+
 // Update is an autogenerated method
 // nolint: dupl
 func (u UserUpdater) Update() error {
@@ -466,26 +822,41 @@ func (f UserDBSchemaField) String() string {
 
 // UserDBSchema stores db field names of User
 var UserDBSchema = struct {
-	ID       UserDBSchemaField
-	Name     UserDBSchemaField
-	Balance  UserDBSchemaField
-	Password UserDBSchemaField
+	ID              UserDBSchemaField
+	Name            UserDBSchemaField
+	Balance         UserDBSchemaField
+	Password        UserDBSchemaField
+	Roles           UserDBSchemaField
+	Status          UserDBSchemaField
+	StatusReason    UserDBSchemaField
+	StatusChangedAt UserDBSchemaField
+	HotBuckets      UserDBSchemaField
 }{
 
-	ID:       UserDBSchemaField("id"),
-	Name:     UserDBSchemaField("name"),
-	Balance:  UserDBSchemaField("balance"),
-	Password: UserDBSchemaField("password"),
+	ID:              UserDBSchemaField("id"),
+	Name:            UserDBSchemaField("name"),
+	Balance:         UserDBSchemaField("balance"),
+	Password:        UserDBSchemaField("password"),
+	Roles:           UserDBSchemaField("roles"),
+	Status:          UserDBSchemaField("status"),
+	StatusReason:    UserDBSchemaField("status_reason"),
+	StatusChangedAt: UserDBSchemaField("status_changed_at"),
+	HotBuckets:      UserDBSchemaField("hot_buckets"),
 }
 
 // Update updates User fields by primary key
 // nolint: dupl
 func (o *User) Update(db *gorm.DB, fields ...UserDBSchemaField) error {
 	dbNameToFieldName := map[string]interface{}{
-		"id":       o.ID,
-		"name":     o.Name,
-		"balance":  o.Balance,
-		"password": o.Password,
+		"id":                o.ID,
+		"name":              o.Name,
+		"balance":           o.Balance,
+		"password":          o.Password,
+		"roles":             o.Roles,
+		"status":            o.Status,
+		"status_reason":     o.StatusReason,
+		"status_changed_at": o.StatusChangedAt,
+		"hot_buckets":       o.HotBuckets,
 	}
 	u := map[string]interface{}{}
 	for _, f := range fields {
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type ListTransactionsInput struct {
 	UserId int64
 }
@@ -16,8 +18,26 @@ type SendMoneyInput struct {
 }
 
 type SendMoneyOutput struct {
-	Success      bool
-	ErrorMessage string
+	Success     bool
+	Transaction Transaction
+}
+
+// TransferLine is one recipient of a batch transfer.
+type TransferLine struct {
+	To     int64
+	Amount int64
+}
+
+type SendMoneyBatchInput struct {
+	From  int64
+	Lines []TransferLine
+}
+
+// SendMoneyBatchOutput holds one transaction per input line, in line order.
+type SendMoneyBatchOutput struct {
+	BatchID      string
+	Total        int64
+	Transactions []Transaction
 }
 
 type GetBalanceInput struct {
@@ -28,3 +48,11 @@ type GetBalanceOutput struct {
 	UserId  int64
 	Balance int64
 }
+
+// TransactionScan bounds a keyset scan over the transactions table. Zero
+// values leave the corresponding side open.
+type TransactionScan struct {
+	ToID  uint
+	Since time.Time
+	Until time.Time
+}
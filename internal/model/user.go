@@ -1,5 +1,10 @@
 package model
 
+import (
+	"strings"
+	"time"
+)
+
 //go:generate goqueryset -in user.go
 
 // gen:qs
@@ -8,4 +13,53 @@ type User struct {
 	Name     string `gorm:"not null"`
 	Balance  int64  `gorm:"not null;default:0"`
 	Password string `gorm:"not null"`
+	// Roles is a space-separated list, e.g. "user admin".
+	Roles string `gorm:"not null;default:user"`
+	// Status is one of the Status* constants. StatusReason and
+	// StatusChangedAt describe the most recent change.
+	Status          string `gorm:"not null;default:active"`
+	StatusReason    string `gorm:"not null;default:''"`
+	StatusChangedAt *time.Time
+	// HotBuckets > 0 makes this a hot account: credits go to one of that
+	// many AccountBucket rows and are swept into Balance later.
+	HotBuckets int `gorm:"not null;default:0"`
+}
+
+// AccountBucket holds credits to a hot account until they are swept into
+// User.Balance. The account's balance is User.Balance plus its buckets.
+type AccountBucket struct {
+	UserID  int64 `gorm:"primaryKey"`
+	Bucket  int   `gorm:"primaryKey"`
+	Balance int64 `gorm:"not null;default:0"`
+}
+
+// MaxHotBuckets bounds User.HotBuckets.
+const MaxHotBuckets = 64
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var KnownRoles = []string{RoleUser, RoleAdmin}
+
+// Account statuses. Active accounts send and receive; receive-only accounts
+// can only receive; frozen accounts can do neither until unfrozen; closed is
+// final.
+const (
+	StatusActive      = "active"
+	StatusFrozen      = "frozen"
+	StatusClosed      = "closed"
+	StatusReceiveOnly = "receive_only"
+)
+
+var AccountStatuses = []string{StatusActive, StatusFrozen, StatusClosed, StatusReceiveOnly}
+
+// SystemAccountID is the user row that admin credits are drawn from and
+// debits are posted to. Its balance may go negative; it cannot log in and
+// is not reachable through the public transfer API.
+const SystemAccountID int64 = 0
+
+func (u User) RoleList() []string {
+	return strings.Fields(u.Roles)
 }
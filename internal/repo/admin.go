@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"project/internal/model"
+	"project/internal/service"
+
+	"gorm.io/gorm"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsers returns up to limit users with ID greater than afterID, in ID
+// order. A numeric query also matches the user ID exactly.
+func (r *GormTransferRepo) SearchUsers(ctx context.Context, query string, afterID int64, limit int) ([]model.User, error) {
+	db := r.db.WithContext(ctx)
+	if query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+			db = db.Where("id = ? OR name ILIKE ?", id, pattern)
+		} else {
+			db = db.Where("name ILIKE ?", pattern)
+		}
+	}
+
+	var users []model.User
+	if err := model.NewUserQuerySet(db).IDGt(afterID).OrderAscByID().Limit(limit).All(&users); err != nil {
+		return nil, err
+	}
+	if err := addBucketBalances(db, users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *GormTransferRepo) GetUser(ctx context.Context, userID int64) (model.User, error) {
+	var user model.User
+	err := model.NewUserQuerySet(r.db.WithContext(ctx)).IDEq(userID).One(&user)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.User{}, service.UserNotFound(userID)
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+	users := []model.User{user}
+	err = addBucketBalances(r.db.WithContext(ctx), users)
+	return users[0], err
+}
+
+// ListUserTransactions returns up to limit transactions sent or received by
+// userID, newest first. A non-zero beforeID continues from a previous page.
+func (r *GormTransferRepo) ListUserTransactions(ctx context.Context, userID int64, beforeID uint, limit int) ([]model.Transaction, error) {
+	qs := model.NewTransactionQuerySet(r.db.WithContext(ctx).Where("from_user = ? OR to_user = ?", userID, userID))
+	if beforeID > 0 {
+		qs = qs.IDLt(beforeID)
+	}
+
+	var txs []model.Transaction
+	if err := qs.OrderDescByID().Limit(limit).All(&txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// AdjustBalance credits (amount > 0) or debits (amount < 0) userID against
+// the system account and records event in the same transaction, with the
+// resulting transaction ID added to its details.
+func (r *GormTransferRepo) AdjustBalance(ctx context.Context, userID, amount int64, event model.AuditEvent) (model.Transaction, int64, error) {
+	var created model.Transaction
+	var balance int64
+	err := r.transaction(ctx, "AdjustBalance", func(tx *gorm.DB) error {
+		from, to, value := model.SystemAccountID, userID, amount
+		if amount < 0 {
+			from, to, value = userID, model.SystemAccountID, -amount
+		}
+		var err error
+		if created, err = moveOne(tx, from, to, value, true); err != nil {
+			return err
+		}
+
+		if balance, err = aggregateBalance(tx, userID); err != nil {
+			return err
+		}
+
+		if event.Details, err = withDetail(event.Details, "transaction_id", created.ID); err != nil {
+			return err
+		}
+		event.Before = stateJSON(map[string]interface{}{"balance": balance - amount})
+		event.After = stateJSON(map[string]interface{}{"balance": balance})
+		return appendAuditEvent(tx, &event)
+	})
+	return created, balance, err
+}
+
+// SetAccountStatus validates and applies a status change with the user row
+// locked, and records event in the same transaction.
+func (r *GormTransferRepo) SetAccountStatus(ctx context.Context, userID int64, status, reason string, at time.Time, event model.AuditEvent) (model.User, error) {
+	var user model.User
+	err := r.transaction(ctx, "SetAccountStatus", func(tx *gorm.DB) error {
+		e := event
+		if err := lockUser(tx, userID, "UPDATE", &user); err != nil {
+			return err
+		}
+		// Closing checks the balance, so unswept credits must count.
+		if err := sweepInto(tx, &user); err != nil {
+			return err
+		}
+		if err := service.ValidateStatusTransition(user, status); err != nil {
+			return err
+		}
+		if err := model.NewUserQuerySet(tx).IDEq(userID).GetUpdater().
+			SetStatus(status).
+			SetStatusReason(reason).
+			SetStatusChangedAt(&at).
+			Update(); err != nil {
+			return err
+		}
+		e.Before = stateJSON(map[string]interface{}{"status": user.Status, "status_reason": user.StatusReason})
+		e.After = stateJSON(map[string]interface{}{"status": status, "status_reason": reason})
+		user.Status, user.StatusReason, user.StatusChangedAt = status, reason, &at
+		return appendAuditEvent(tx, &e)
+	})
+	return user, err
+}
+
+func withDetail(details, key string, value interface{}) (string, error) {
+	m := map[string]interface{}{}
+	if details != "" {
+		if err := json.Unmarshal([]byte(details), &m); err != nil {
+			return "", err
+		}
+	}
+	m[key] = value
+	out, err := json.Marshal(m)
+	return string(out), err
+}
+
+func stateJSON(state map[string]interface{}) string {
+	out, _ := json.Marshal(state)
+	return string(out)
+}
@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"project/internal/model"
+	"project/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// lastUsedGranularity limits last_used_at writes to one per key per minute.
+const lastUsedGranularity = time.Minute
+
+type GormAPIKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewPostgresAPIKeyRepo(db *gorm.DB) *GormAPIKeyRepo {
+	return &GormAPIKeyRepo{db: db}
+}
+
+func (r *GormAPIKeyRepo) EnsureServiceAccount(ctx context.Context, ownerID int64, name string) (model.ServiceAccount, error) {
+	account := model.ServiceAccount{OwnerID: ownerID, Name: name}
+	err := r.db.WithContext(ctx).
+		Where(model.ServiceAccount{OwnerID: ownerID, Name: name}).
+		FirstOrCreate(&account).Error
+	return account, err
+}
+
+func (r *GormAPIKeyRepo) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	return key.Create(r.db.WithContext(ctx))
+}
+
+func (r *GormAPIKeyRepo) records(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("api_keys").
+		Select("api_keys.*, service_accounts.name AS service_account_name, service_accounts.owner_id AS owner_id").
+		Joins("JOIN service_accounts ON service_accounts.id = api_keys.service_account_id")
+}
+
+func (r *GormAPIKeyRepo) ListAPIKeys(ctx context.Context, ownerID int64) ([]model.APIKeyRecord, error) {
+	var keys []model.APIKeyRecord
+	err := r.records(ctx).
+		Where("service_accounts.owner_id = ?", ownerID).
+		Order("api_keys.id").
+		Scan(&keys).Error
+	return keys, err
+}
+
+func (r *GormAPIKeyRepo) FindAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKeyRecord, error) {
+	return r.findAPIKey(ctx, "api_keys.prefix = ?", prefix)
+}
+
+func (r *GormAPIKeyRepo) FindAPIKeyByID(ctx context.Context, id int64) (model.APIKeyRecord, error) {
+	return r.findAPIKey(ctx, "api_keys.id = ?", id)
+}
+
+func (r *GormAPIKeyRepo) findAPIKey(ctx context.Context, query string, arg interface{}) (model.APIKeyRecord, error) {
+	var keys []model.APIKeyRecord
+	if err := r.records(ctx).Where(query, arg).Limit(1).Scan(&keys).Error; err != nil {
+		return model.APIKeyRecord{}, err
+	}
+	if len(keys) == 0 {
+		return model.APIKeyRecord{}, gorm.ErrRecordNotFound
+	}
+	return keys[0], nil
+}
+
+// RevokeAPIKey revokes a key owned by ownerID. Keys of other owners are
+// reported as not found.
+func (r *GormAPIKeyRepo) RevokeAPIKey(ctx context.Context, ownerID, id int64, at time.Time) error {
+	key, err := r.FindAPIKeyByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && key.OwnerID != ownerID) {
+		return service.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return model.NewAPIKeyQuerySet(r.db.WithContext(ctx)).
+		IDEq(id).
+		RevokedAtIsNull().
+		GetUpdater().
+		SetRevokedAt(&at).
+		Update()
+}
+
+func (r *GormAPIKeyRepo) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-lastUsedGranularity)).
+		Update("last_used_at", at).Error
+}
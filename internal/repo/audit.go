@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"project/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// auditChainLock is the transaction-level advisory lock that serialises
+// appends, so every entry is chained to the one committed before it.
+const auditChainLock int64 = 0x61756469745f6c67
+
+// auditPendingTable holds events queued by queueAuditEvent until
+// ChainAuditEvents moves them into the chain.
+const auditPendingTable = "audit_pending"
+
+var auditEventsChained = expvar.NewInt("audit_events_chained")
+
+// appendAuditEvent chains event to the latest entry and inserts it. It must
+// run inside tx's transaction, after any row locks the caller needs.
+func appendAuditEvent(tx *gorm.DB, event *model.AuditEvent) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+		return err
+	}
+
+	prevHash := model.AuditGenesisHash
+	var last model.AuditEvent
+	err := model.NewAuditEventQuerySet(tx).Select(model.AuditEventDBSchema.Hash).OrderDescByID().Limit(1).One(&last)
+	switch {
+	case err == nil:
+		prevHash = last.Hash
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	if event.Details == "" {
+		event.Details = "{}"
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	event.CreatedAt = event.CreatedAt.UTC().Truncate(time.Microsecond)
+	event.PrevHash = prevHash
+	event.Hash = event.ComputeHash(prevHash)
+	return tx.Create(event).Error
+}
+
+// queueAuditEvent records event in tx's transaction without taking the chain
+// lock, which every transfer would otherwise queue on. The event keeps its
+// creation time; ChainAuditEvents adds it to the chain later.
+func queueAuditEvent(tx *gorm.DB, event model.AuditEvent) error {
+	if event.Details == "" {
+		event.Details = "{}"
+	}
+	event.ID = 0
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return tx.Table(auditPendingTable).Omit("PrevHash", "Hash").Create(&event).Error
+}
+
+// ChainAuditEvents moves up to limit queued events into audit_events in the
+// order they were queued and returns how many it moved. Chainers on other
+// replicas wait for the chain lock and then see the queue without them.
+func (r *GormTransferRepo) ChainAuditEvents(ctx context.Context, limit int) (int, error) {
+	var chained int
+	err := r.transaction(ctx, "ChainAuditEvents", func(tx *gorm.DB) error {
+		chained = 0
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+		var pending []model.AuditEvent
+		if err := tx.Table(auditPendingTable).Order("id").Limit(limit).Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(pending))
+		for i := range pending {
+			ids[i] = pending[i].ID
+			pending[i].ID = 0
+			if err := appendAuditEvent(tx, &pending[i]); err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM audit_pending WHERE id IN ?", ids).Error; err != nil {
+			return err
+		}
+		chained = len(pending)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	auditEventsChained.Add(int64(chained))
+	return chained, nil
+}
+
+func (r *GormTransferRepo) RecordAuditEvent(ctx context.Context, event model.AuditEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return appendAuditEvent(tx, &event)
+	})
+}
+
+// ListAuditEvents returns up to limit events matching filter, newest first. A
+// non-zero beforeID continues from a previous page.
+func (r *GormTransferRepo) ListAuditEvents(ctx context.Context, filter model.AuditFilter, beforeID int64, limit int) ([]model.AuditEvent, error) {
+	qs := model.NewAuditEventQuerySet(r.db.WithContext(ctx))
+	if filter.ActorID > 0 {
+		qs = qs.ActorIDEq(filter.ActorID)
+	}
+	if filter.TargetUserID > 0 {
+		qs = qs.TargetUserIDEq(filter.TargetUserID)
+	}
+	if filter.Action != "" {
+		qs = qs.ActionEq(filter.Action)
+	}
+	if !filter.Since.IsZero() {
+		qs = qs.CreatedAtGte(filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		qs = qs.CreatedAtLt(filter.Until)
+	}
+	if beforeID > 0 {
+		qs = qs.IDLt(beforeID)
+	}
+
+	var events []model.AuditEvent
+	if err := qs.OrderDescByID().Limit(limit).All(&events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ScanAuditEvents returns up to limit events with ID greater than afterID, in
+// chain order.
+func (r *GormTransferRepo) ScanAuditEvents(ctx context.Context, afterID int64, limit int) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+	err := model.NewAuditEventQuerySet(r.db.WithContext(ctx)).IDGt(afterID).OrderAscByID().Limit(limit).All(&events)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
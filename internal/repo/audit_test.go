@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAuditEvent_Chain(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &GormTransferRepo{db: db}
+	ctx := context.Background()
+	id := createTestUsers(t, db, 0)[0]
+
+	require.NoError(t, repo.RecordAuditEvent(ctx, model.AuditEvent{ActorID: id, Action: model.AuditLogin, TargetUserID: id}))
+	require.NoError(t, repo.RecordAuditEvent(ctx, model.AuditEvent{ActorID: id, Action: model.AuditLogout, TargetUserID: id}))
+
+	events, err := repo.ListAuditEvents(ctx, model.AuditFilter{ActorID: id}, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	last, prev := events[0], events[1]
+	require.Equal(t, prev.Hash, last.PrevHash)
+	require.Equal(t, last.ComputeHash(last.PrevHash), last.Hash)
+
+	err = db.Exec("UPDATE audit_events SET reason = 'edited' WHERE id = ?", last.ID).Error
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestChainAuditEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransferRepo(db, config.LoadConfig())
+	ctx := context.Background()
+	ids := createTestUsers(t, db, 100, 0)
+	from, to := ids[0], ids[1]
+
+	// Drain anything earlier tests queued.
+	for {
+		n, err := repo.ChainAuditEvents(ctx, 500)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	tx, err := repo.InsertTransaction(ctx, from, to, 10, model.AuditEvent{ActorID: from, Action: model.AuditTransfer, TargetUserID: to})
+	require.NoError(t, err)
+	_, err = repo.InsertTransactions(ctx, from, []model.TransferLine{{To: to, Amount: 5}}, model.AuditEvent{ActorID: from, Action: model.AuditTransferBatch})
+	require.NoError(t, err)
+	// A failed transfer queues nothing.
+	_, err = repo.InsertTransaction(ctx, from, to, 1000, model.AuditEvent{ActorID: from, Action: model.AuditTransfer, TargetUserID: to})
+	require.Error(t, err)
+
+	events, err := repo.ListAuditEvents(ctx, model.AuditFilter{ActorID: from}, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, events, "queued events are not in the chain yet")
+
+	n, err := repo.ChainAuditEvents(ctx, 500)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	events, err = repo.ListAuditEvents(ctx, model.AuditFilter{ActorID: from}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, model.AuditTransferBatch, events[0].Action)
+	require.Equal(t, model.AuditTransfer, events[1].Action)
+	require.Contains(t, events[1].Details, fmt.Sprintf(`"transaction_id":%d`, tx.ID))
+	require.Equal(t, events[1].Hash, events[0].PrevHash)
+
+	var pending int64
+	require.NoError(t, db.Table(auditPendingTable).Count(&pending).Error)
+	require.Zero(t, pending)
+	_, err = service.VerifyAuditChain(ctx, repo, 1000)
+	require.NoError(t, err)
+}
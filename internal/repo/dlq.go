@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deadLetterLease is how long pulled dead letters stay hidden from other
+// pullers. Commands working through the subscription page by page refresh
+// it for the messages they still hold before every pull.
+const deadLetterLease = 60
+
+// DeadLetterPageSize bounds one PullDeadLetters call, and the number of ack
+// IDs sent per acknowledge or deadline request.
+const DeadLetterPageSize = 100
+
+type DeadLetter struct {
+	AckID       string
+	MessageID   string
+	OrderingKey string
+	PublishTime time.Time
+	Attributes  map[string]string
+	Data        []byte
+}
+
+// PullDeadLetters leases one page of at most max (and DeadLetterPageSize)
+// messages from the dead-letter subscription. An empty page means nothing
+// arrived within the pull timeout. Callers must Ack, Extend or Release
+// every returned message.
+func (p *PubSub) PullDeadLetters(ctx context.Context, max int) ([]DeadLetter, error) {
+	if max <= 0 || max > DeadLetterPageSize {
+		max = DeadLetterPageSize
+	}
+	subPath := p.subscriptionPath(p.config.PubSub.DeadLetterSubscription)
+
+	pullCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	resp, err := p.subClient.Pull(pullCtx, &pubsubpb.PullRequest{
+		Subscription: subPath,
+		MaxMessages:  int32(max),
+	})
+	cancel()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("pull dead letters: %w", err)
+	}
+	if resp == nil || len(resp.ReceivedMessages) == 0 {
+		return nil, nil
+	}
+
+	out := make([]DeadLetter, 0, len(resp.ReceivedMessages))
+	for _, m := range resp.ReceivedMessages {
+		out = append(out, DeadLetter{
+			AckID:       m.AckId,
+			MessageID:   m.Message.MessageId,
+			OrderingKey: m.Message.OrderingKey,
+			PublishTime: m.Message.PublishTime.AsTime(),
+			Attributes:  m.Message.Attributes,
+			Data:        m.Message.Data,
+		})
+	}
+	if err := p.ExtendDeadLetters(ctx, out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ExtendDeadLetters restarts the lease on messages the caller still holds.
+func (p *PubSub) ExtendDeadLetters(ctx context.Context, msgs []DeadLetter) error {
+	subPath := p.subscriptionPath(p.config.PubSub.DeadLetterSubscription)
+	return forEachAckIDPage(msgs, func(ids []string) error {
+		if err := p.subClient.ModifyAckDeadline(ctx, &pubsubpb.ModifyAckDeadlineRequest{
+			Subscription:       subPath,
+			AckIds:             ids,
+			AckDeadlineSeconds: deadLetterLease,
+		}); err != nil {
+			return fmt.Errorf("extend dead letter lease: %w", err)
+		}
+		return nil
+	})
+}
+
+// AckDeadLetters removes messages from the dead-letter subscription.
+func (p *PubSub) AckDeadLetters(ctx context.Context, msgs []DeadLetter) error {
+	subPath := p.subscriptionPath(p.config.PubSub.DeadLetterSubscription)
+	return forEachAckIDPage(msgs, func(ids []string) error {
+		return p.subClient.Acknowledge(ctx, &pubsubpb.AcknowledgeRequest{
+			Subscription: subPath,
+			AckIds:       ids,
+		})
+	})
+}
+
+// ReleaseDeadLetters returns leased messages to the dead-letter subscription
+// untouched.
+func (p *PubSub) ReleaseDeadLetters(ctx context.Context, msgs []DeadLetter) error {
+	subPath := p.subscriptionPath(p.config.PubSub.DeadLetterSubscription)
+	return forEachAckIDPage(msgs, func(ids []string) error {
+		return p.nack(ctx, subPath, ids)
+	})
+}
+
+// Republish sends a dead letter back to the main topic with its original
+// attributes, minus the ones Pub/Sub adds when dead-lettering.
+func (p *PubSub) Republish(msg DeadLetter) error {
+	attrs := make(map[string]string, len(msg.Attributes))
+	for k, v := range msg.Attributes {
+		if strings.HasPrefix(k, "CloudPubSubDeadLetter") {
+			continue
+		}
+		attrs[k] = v
+	}
+	return p.publish(p.topicPath(p.config.PubSub.Topic), &pubsubpb.PubsubMessage{
+		Data:        msg.Data,
+		Attributes:  attrs,
+		OrderingKey: msg.OrderingKey,
+	})
+}
+
+// forEachAckIDPage calls fn with the ack IDs of msgs, DeadLetterPageSize at
+// a time.
+func forEachAckIDPage(msgs []DeadLetter, fn func(ids []string) error) error {
+	for len(msgs) > 0 {
+		n := min(len(msgs), DeadLetterPageSize)
+		ids := make([]string, 0, n)
+		for _, m := range msgs[:n] {
+			ids = append(ids, m.AckID)
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+		msgs = msgs[n:]
+	}
+	return nil
+}
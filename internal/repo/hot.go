@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"math/rand"
+
+	"project/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// Hot accounts take credits into account_buckets rows so that concurrent
+// transfers to them do not serialise on the users row. Lock order is always
+// the users row, then buckets:
+//
+//   - a credit holds the recipient row FOR KEY SHARE, which other credits
+//     and the sweeper do not conflict with, then upserts one bucket;
+//   - a debit, status change or mode change holds the row FOR UPDATE, which
+//     waits for in-flight credits and keeps new ones out, so the buckets it
+//     sweeps are stable;
+//   - the sweeper holds the row FOR NO KEY UPDATE, so it queues behind debits
+//     but not credits, then empties the buckets into users.balance.
+var (
+	hotAccountSweeps      = expvar.NewInt("hot_account_sweeps")
+	hotAccountSweptAmount = expvar.NewInt("hot_account_swept_amount")
+)
+
+// recipientBuckets reads the bucket counts of the hot accounts among ids
+// without locking, to choose the locks moveMoney takes. moveMoney re-reads
+// the count from the locked row before crediting.
+func recipientBuckets(tx *gorm.DB, ids []int64) (map[int64]int, error) {
+	var rows []struct {
+		ID         int64
+		HotBuckets int
+	}
+	if err := tx.Model(&model.User{}).Select("id, hot_buckets").
+		Where("id IN ? AND hot_buckets > 0", ids).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	hot := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		hot[row.ID] = row.HotBuckets
+	}
+	return hot, nil
+}
+
+func creditBucket(tx *gorm.DB, userID int64, buckets int, amount int64) error {
+	return tx.Exec(`INSERT INTO account_buckets (user_id, bucket, balance) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, bucket) DO UPDATE SET balance = account_buckets.balance + EXCLUDED.balance`,
+		userID, rand.Intn(buckets), amount).Error
+}
+
+// sweepBuckets empties userID's buckets and returns what they held. The
+// caller holds the users row and adds the result to its balance.
+func sweepBuckets(tx *gorm.DB, userID int64) (int64, error) {
+	var total int64
+	err := tx.Raw(`WITH swept AS (
+			UPDATE account_buckets SET balance = 0 WHERE user_id = ? AND balance > 0 RETURNING balance
+		) SELECT COALESCE(SUM(balance), 0) FROM swept`, userID).Row().Scan(&total)
+	return total, err
+}
+
+// sweepInto sweeps user's buckets into its balance; the caller holds the
+// users row FOR UPDATE.
+func sweepInto(tx *gorm.DB, user *model.User) error {
+	swept, err := sweepBuckets(tx, user.ID)
+	if err != nil || swept == 0 {
+		return err
+	}
+	if err := model.NewUserQuerySet(tx).IDEq(user.ID).GetUpdater().SetBalance(user.Balance + swept).Update(); err != nil {
+		return err
+	}
+	user.Balance += swept
+	return nil
+}
+
+// aggregateBalance returns users.balance plus unswept credits.
+func aggregateBalance(tx *gorm.DB, userID int64) (int64, error) {
+	var balance int64
+	err := tx.Raw(`SELECT u.balance + COALESCE((SELECT SUM(b.balance) FROM account_buckets b WHERE b.user_id = u.id), 0)
+		FROM users u WHERE u.id = ?`, userID).Row().Scan(&balance)
+	return balance, err
+}
+
+// addBucketBalances adds unswept credits to the balances of users.
+func addBucketBalances(db *gorm.DB, users []model.User) error {
+	var ids []int64
+	for _, u := range users {
+		if u.HotBuckets > 0 {
+			ids = append(ids, u.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	var sums []struct {
+		UserID int64
+		Total  int64
+	}
+	if err := db.Model(&model.AccountBucket{}).Select("user_id, SUM(balance) AS total").
+		Where("user_id IN ?", ids).Group("user_id").Scan(&sums).Error; err != nil {
+		return err
+	}
+	for _, s := range sums {
+		for i := range users {
+			if users[i].ID == s.UserID {
+				users[i].Balance += s.Total
+			}
+		}
+	}
+	return nil
+}
+
+// SweepHotAccounts moves every non-empty bucket into its account's balance
+// and returns how many accounts it swept.
+func (r *GormTransferRepo) SweepHotAccounts(ctx context.Context) (int, error) {
+	var ids []int64
+	if err := r.db.WithContext(ctx).Model(&model.AccountBucket{}).
+		Where("balance > 0").Distinct().Pluck("user_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	swept := 0
+	for _, id := range ids {
+		err := r.transaction(ctx, "SweepHotAccounts", func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR NO KEY UPDATE", id).Error; err != nil {
+				return err
+			}
+			total, err := sweepBuckets(tx, id)
+			if err != nil || total == 0 {
+				return err
+			}
+			if err := tx.Exec("UPDATE users SET balance = balance + ? WHERE id = ?", total, id).Error; err != nil {
+				return err
+			}
+			hotAccountSweptAmount.Add(total)
+			return nil
+		})
+		if err != nil {
+			return swept, err
+		}
+		swept++
+	}
+	hotAccountSweeps.Add(int64(swept))
+	return swept, nil
+}
+
+// SetHotBuckets sets userID's bucket count and records event. Turning hot
+// mode off sweeps the buckets first.
+func (r *GormTransferRepo) SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error {
+	return r.transaction(ctx, "SetHotBuckets", func(tx *gorm.DB) error {
+		var user model.User
+		if err := lockUser(tx, userID, "UPDATE", &user); err != nil {
+			return err
+		}
+		if buckets == 0 {
+			if err := sweepInto(tx, &user); err != nil {
+				return err
+			}
+		}
+		if err := model.NewUserQuerySet(tx).IDEq(userID).GetUpdater().SetHotBuckets(buckets).Update(); err != nil {
+			return err
+		}
+		log.Printf("[Repo] user=%d hot buckets %d -> %d", userID, user.HotBuckets, buckets)
+		event.Before = stateJSON(map[string]interface{}{"hot_buckets": user.HotBuckets})
+		event.After = stateJSON(map[string]interface{}{"hot_buckets": buckets})
+		return appendAuditEvent(tx, &event)
+	})
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hotAccountRepo interface {
+	InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error)
+	GetBalance(ctx context.Context, userID int64) (int64, error)
+	GetUser(ctx context.Context, userID int64) (model.User, error)
+	SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error
+	SetAccountStatus(ctx context.Context, userID int64, status, reason string, at time.Time, event model.AuditEvent) (model.User, error)
+	SweepHotAccounts(ctx context.Context) (int, error)
+}
+
+func TestHotAccounts(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		r := NewMemoryTransferRepo()
+		for i, balance := range []int64{100, 100, 0} {
+			require.NoError(t, r.SeedUser(model.User{ID: int64(i + 1), Name: "hot", Balance: balance}))
+		}
+		testHotAccounts(t, r, []int64{1, 2, 3})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		db := setupTestDB(t)
+		testHotAccounts(t, NewPostgresTransferRepo(db, config.LoadConfig()), createTestUsers(t, db, 100, 100, 0))
+	})
+}
+
+// testHotAccounts credits a hot merchant account from two customers and
+// checks that balances, debits and closing see the unswept credits.
+func testHotAccounts(t *testing.T, r hotAccountRepo, ids []int64) {
+	ctx := context.Background()
+	a, b, shop := ids[0], ids[1], ids[2]
+	balance := func(id int64) int64 {
+		t.Helper()
+		got, err := r.GetBalance(ctx, id)
+		require.NoError(t, err)
+		return got
+	}
+
+	err := r.SetHotBuckets(ctx, 1<<40, 4, model.AuditEvent{Action: model.AuditSetHotBuckets})
+	require.ErrorIs(t, err, &service.Error{Reason: "USER_NOT_FOUND"})
+	require.NoError(t, r.SetHotBuckets(ctx, shop, 4, model.AuditEvent{Action: model.AuditSetHotBuckets}))
+
+	for i := 0; i < 10; i++ {
+		_, err := r.InsertTransaction(ctx, a, shop, 3, model.AuditEvent{})
+		require.NoError(t, err)
+		_, err = r.InsertTransaction(ctx, b, shop, 2, model.AuditEvent{})
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 50, balance(shop))
+	u, err := r.GetUser(ctx, shop)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, u.Balance)
+	require.Equal(t, 4, u.HotBuckets)
+
+	// A debit beyond the swept balance draws on the buckets.
+	_, err = r.InsertTransaction(ctx, shop, a, 51, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrInsufficientFunds)
+	_, err = r.InsertTransaction(ctx, shop, a, 45, model.AuditEvent{})
+	require.NoError(t, err)
+	require.EqualValues(t, 5, balance(shop))
+	require.EqualValues(t, 115, balance(a))
+
+	_, err = r.InsertTransaction(ctx, b, shop, 7, model.AuditEvent{})
+	require.NoError(t, err)
+	_, err = r.SweepHotAccounts(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, balance(shop))
+
+	// Closing requires a zero aggregate balance, unswept credits included.
+	_, err = r.InsertTransaction(ctx, a, shop, 1, model.AuditEvent{})
+	require.NoError(t, err)
+	_, err = r.InsertTransaction(ctx, shop, b, 12, model.AuditEvent{})
+	require.NoError(t, err)
+	_, err = r.SetAccountStatus(ctx, shop, model.StatusClosed, "", time.Now(), model.AuditEvent{Action: model.AuditSetAccountStatus})
+	require.ErrorIs(t, err, service.ErrBalanceNotZero)
+
+	require.NoError(t, r.SetHotBuckets(ctx, shop, 0, model.AuditEvent{Action: model.AuditSetHotBuckets}))
+	require.EqualValues(t, 1, balance(shop))
+	require.EqualValues(t, 200, balance(a)+balance(b)+balance(shop))
+}
+
+// A credit that read hot_buckets before SetHotBuckets(0) committed must go
+// to users.balance, or addBucketBalances would hide it until the next sweep.
+func TestHotAccounts_CreditRacesModeOff(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewPostgresTransferRepo(db, config.LoadConfig())
+	ctx := context.Background()
+	ids := createTestUsers(t, db, 100, 0)
+	from, shop := ids[0], ids[1]
+	require.NoError(t, r.SetHotBuckets(ctx, shop, 4, model.AuditEvent{Action: model.AuditSetHotBuckets}))
+
+	modeOff := db.Begin()
+	require.NoError(t, modeOff.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", shop).Error)
+	require.NoError(t, modeOff.Exec("UPDATE users SET hot_buckets = 0 WHERE id = ?", shop).Error)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := r.InsertTransaction(ctx, from, shop, 10, model.AuditEvent{})
+		errc <- err
+	}()
+	require.Eventually(t, func() bool {
+		var waiting int64
+		db.Raw(`SELECT COUNT(*) FROM pg_stat_activity
+			WHERE datname = current_database() AND wait_event_type = 'Lock'`).Scan(&waiting)
+		return waiting > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, modeOff.Commit().Error)
+	require.NoError(t, <-errc)
+
+	u, err := r.GetUser(ctx, shop)
+	require.NoError(t, err)
+	require.Zero(t, u.HotBuckets)
+	require.EqualValues(t, 10, u.Balance)
+	var inBuckets int64
+	require.NoError(t, db.Raw("SELECT COALESCE(SUM(balance), 0) FROM account_buckets WHERE user_id = ?", shop).Scan(&inBuckets).Error)
+	require.Zero(t, inBuckets)
+}
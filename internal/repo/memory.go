@@ -0,0 +1,510 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"project/internal/model"
+	"project/internal/service"
+)
+
+// MemoryTransferRepo is an in-process stand-in for GormTransferRepo, used by
+// the dev command and tests. One mutex serialises every call, which gives
+// each method the atomicity of a database transaction. Data is lost when the
+// process exits.
+type MemoryTransferRepo struct {
+	mu      sync.Mutex
+	users   map[int64]*model.User
+	buckets map[int64]map[int]int64 // hot account credits not yet swept
+	nextID  int64
+	txs     []model.Transaction
+	audit   []model.AuditEvent
+	outbox  []Message
+
+	// relayMu keeps one RelayEvents at a time; it does not hold mu while
+	// sending.
+	relayMu sync.Mutex
+}
+
+// NewMemoryTransferRepo returns a repository holding only the system
+// account, as after `migrate up`.
+func NewMemoryTransferRepo() *MemoryTransferRepo {
+	return &MemoryTransferRepo{
+		users: map[int64]*model.User{
+			model.SystemAccountID: {
+				ID:       model.SystemAccountID,
+				Name:     "system",
+				Password: "!",
+				Roles:    "system",
+				Status:   model.StatusActive,
+			},
+		},
+		buckets: map[int64]map[int]int64{},
+		nextID:  1,
+	}
+}
+
+func (r *MemoryTransferRepo) user(userID int64) (*model.User, error) {
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, service.UserNotFound(userID)
+	}
+	return u, nil
+}
+
+// balance returns u's balance including unswept credits; callers hold mu.
+func (r *MemoryTransferRepo) balance(u *model.User) int64 {
+	total := u.Balance
+	for _, b := range r.buckets[u.ID] {
+		total += b
+	}
+	return total
+}
+
+// sweep moves u's buckets into its balance; callers hold mu.
+func (r *MemoryTransferRepo) sweep(u *model.User) int64 {
+	var swept int64
+	for _, b := range r.buckets[u.ID] {
+		swept += b
+	}
+	delete(r.buckets, u.ID)
+	u.Balance += swept
+	return swept
+}
+
+func (r *MemoryTransferRepo) ListTransactions(ctx context.Context, from int64) ([]model.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Transaction
+	for _, tx := range r.txs {
+		if tx.From == from {
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+func (r *MemoryTransferRepo) ScanTransactions(ctx context.Context, afterID uint, scan model.TransactionScan, limit int) ([]model.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Transaction
+	for _, tx := range r.txs {
+		if len(out) == limit {
+			break
+		}
+		if tx.ID <= afterID || (scan.ToID > 0 && tx.ID > scan.ToID) {
+			continue
+		}
+		if (!scan.Since.IsZero() && tx.CreatedAt.Before(scan.Since)) || (!scan.Until.IsZero() && !tx.CreatedAt.Before(scan.Until)) {
+			continue
+		}
+		out = append(out, tx)
+	}
+	return out, nil
+}
+
+// InsertTransaction chains event straight away; with one mutex there is no
+// chain lock for transfers to queue on, so nothing is left for a chainer.
+func (r *MemoryTransferRepo) InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created, err := r.moveOne(from, to, amount, false)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if event.Details, err = withDetail(event.Details, "transaction_id", created.ID); err != nil {
+		return model.Transaction{}, err
+	}
+	r.appendAuditEvent(&event)
+	return created, nil
+}
+
+func (r *MemoryTransferRepo) InsertTransactions(ctx context.Context, from int64, lines []model.TransferLine, event model.AuditEvent) ([]model.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created, err := r.moveMoney(transfer{from: from, lines: lines, batch: true})
+	if err != nil {
+		return nil, err
+	}
+	if event.Details, err = withDetail(event.Details, "batch_id", model.BatchID(created)); err != nil {
+		return nil, err
+	}
+	r.appendAuditEvent(&event)
+	return created, nil
+}
+
+func (r *MemoryTransferRepo) moveOne(from, to, amount int64, adjustment bool) (model.Transaction, error) {
+	txs, err := r.moveMoney(singleTransfer(from, to, amount, adjustment))
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return txs[0], nil
+}
+
+// moveMoney mirrors the Postgres moveMoney; callers hold mu. Every check
+// runs before anything changes, so a failed transfer leaves no trace.
+func (r *MemoryTransferRepo) moveMoney(t transfer) ([]model.Transaction, error) {
+	fromUser, err := r.user(t.from)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSender(fromUser, t.adjustment); err != nil {
+		return nil, err
+	}
+	for _, l := range t.lines {
+		toUser, err := r.user(l.To)
+		if err != nil {
+			return nil, t.lineError(l.To, err)
+		}
+		if err := checkRecipient(toUser, t.adjustment); err != nil {
+			return nil, t.lineError(l.To, err)
+		}
+	}
+	total := t.total()
+	overdraft := t.adjustment && fromUser.ID == model.SystemAccountID
+	if !overdraft && r.balance(fromUser) < total {
+		return nil, service.ErrInsufficientFunds
+	}
+	if !overdraft && fromUser.Balance < total {
+		r.sweep(fromUser)
+	}
+
+	fromUser.Balance -= total
+	txs := make([]model.Transaction, len(t.lines))
+	for i, l := range t.lines {
+		toUser := r.users[l.To]
+		if toUser.HotBuckets > 0 && toUser.ID != t.from {
+			if r.buckets[l.To] == nil {
+				r.buckets[l.To] = map[int]int64{}
+			}
+			r.buckets[l.To][rand.Intn(toUser.HotBuckets)] += l.Amount
+		} else {
+			toUser.Balance += l.Amount
+		}
+		txs[i] = model.Transaction{
+			ID:        uint(len(r.txs) + 1),
+			From:      t.from,
+			To:        l.To,
+			Amount:    l.Amount,
+			CreatedAt: time.Now().UTC(),
+		}
+		r.txs = append(r.txs, txs[i])
+	}
+	msgs, err := transferMessages(t, txs)
+	if err != nil {
+		return nil, err
+	}
+	r.outbox = append(r.outbox, msgs...)
+	return txs, nil
+}
+
+// RelayEvents mirrors the Postgres RelayEvents. New messages only ever join
+// the tail of the outbox, so the head sent is still the head afterwards.
+func (r *MemoryTransferRepo) RelayEvents(ctx context.Context, limit int, send func(msgs []Message) error) (int, error) {
+	r.relayMu.Lock()
+	defer r.relayMu.Unlock()
+
+	r.mu.Lock()
+	msgs := append([]Message(nil), r.outbox[:min(limit, len(r.outbox))]...)
+	r.mu.Unlock()
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+	if err := send(msgs); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.outbox = r.outbox[len(msgs):]
+	r.mu.Unlock()
+	eventsRelayed.Add(int64(len(msgs)))
+	return len(msgs), nil
+}
+
+func (r *MemoryTransferRepo) GetBalance(ctx context.Context, userID int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return 0, err
+	}
+	return r.balance(u), nil
+}
+
+func (r *MemoryTransferRepo) GetPassword(ctx context.Context, userID int64) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return "", err
+	}
+	return u.Password, nil
+}
+
+func (r *MemoryTransferRepo) GetAccountStatus(ctx context.Context, userID int64) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return "", err
+	}
+	return u.Status, nil
+}
+
+func (r *MemoryTransferRepo) GetRoles(ctx context.Context, userID int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.RoleList(), nil
+}
+
+func (r *MemoryTransferRepo) SearchUsers(ctx context.Context, query string, afterID int64, limit int) ([]model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, idErr := strconv.ParseInt(query, 10, 64)
+	needle := strings.ToLower(query)
+
+	var out []model.User
+	for _, u := range r.users {
+		if u.ID <= afterID {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(u.Name), needle) && (idErr != nil || u.ID != id) {
+			continue
+		}
+		user := *u
+		user.Balance = r.balance(u)
+		out = append(out, user)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *MemoryTransferRepo) GetUser(ctx context.Context, userID int64) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	user := *u
+	user.Balance = r.balance(u)
+	return user, nil
+}
+
+func (r *MemoryTransferRepo) ListUserTransactions(ctx context.Context, userID int64, beforeID uint, limit int) ([]model.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Transaction
+	for i := len(r.txs) - 1; i >= 0 && len(out) < limit; i-- {
+		tx := r.txs[i]
+		if (beforeID == 0 || tx.ID < beforeID) && (tx.From == userID || tx.To == userID) {
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+func (r *MemoryTransferRepo) AdjustBalance(ctx context.Context, userID, amount int64, event model.AuditEvent) (model.Transaction, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	from, to, value := model.SystemAccountID, userID, amount
+	if amount < 0 {
+		from, to, value = userID, model.SystemAccountID, -amount
+	}
+	created, err := r.moveOne(from, to, value, true)
+	if err != nil {
+		return model.Transaction{}, 0, err
+	}
+	balance := r.balance(r.users[userID])
+
+	if event.Details, err = withDetail(event.Details, "transaction_id", created.ID); err != nil {
+		return model.Transaction{}, 0, err
+	}
+	event.Before = stateJSON(map[string]interface{}{"balance": balance - amount})
+	event.After = stateJSON(map[string]interface{}{"balance": balance})
+	r.appendAuditEvent(&event)
+	return created, balance, nil
+}
+
+func (r *MemoryTransferRepo) SetAccountStatus(ctx context.Context, userID int64, status, reason string, at time.Time, event model.AuditEvent) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	r.sweep(u)
+	if err := service.ValidateStatusTransition(*u, status); err != nil {
+		return model.User{}, err
+	}
+	event.Before = stateJSON(map[string]interface{}{"status": u.Status, "status_reason": u.StatusReason})
+	event.After = stateJSON(map[string]interface{}{"status": status, "status_reason": reason})
+	u.Status, u.StatusReason, u.StatusChangedAt = status, reason, &at
+	r.appendAuditEvent(&event)
+	return *u, nil
+}
+
+func (r *MemoryTransferRepo) CreateUser(ctx context.Context, user *model.User, balance int64, event model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.Status == "" {
+		user.Status = model.StatusActive
+	}
+	if user.Roles == "" {
+		user.Roles = model.RoleUser
+	}
+	user.ID = r.nextID
+	user.Balance = 0
+	stored := *user
+	r.users[user.ID] = &stored
+	r.nextID++
+
+	if balance > 0 {
+		created, err := r.moveOne(model.SystemAccountID, user.ID, balance, true)
+		if err != nil {
+			delete(r.users, user.ID)
+			return err
+		}
+		if event.Details, err = withDetail(event.Details, "transaction_id", created.ID); err != nil {
+			return err
+		}
+		user.Balance = balance
+	}
+	event.TargetUserID = user.ID
+	event.After = stateJSON(map[string]interface{}{"name": user.Name, "roles": user.Roles, "balance": user.Balance})
+	r.appendAuditEvent(&event)
+	return nil
+}
+
+func (r *MemoryTransferRepo) SetPassword(ctx context.Context, userID int64, hash string, event model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+	u.Password = hash
+	r.appendAuditEvent(&event)
+	return nil
+}
+
+func (r *MemoryTransferRepo) SweepHotAccounts(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	swept := 0
+	for id := range r.buckets {
+		if amount := r.sweep(r.users[id]); amount > 0 {
+			hotAccountSweptAmount.Add(amount)
+			swept++
+		}
+	}
+	hotAccountSweeps.Add(int64(swept))
+	return swept, nil
+}
+
+func (r *MemoryTransferRepo) SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+	if buckets == 0 {
+		r.sweep(u)
+	}
+	event.Before = stateJSON(map[string]interface{}{"hot_buckets": u.HotBuckets})
+	event.After = stateJSON(map[string]interface{}{"hot_buckets": buckets})
+	u.HotBuckets = buckets
+	r.appendAuditEvent(&event)
+	return nil
+}
+
+// appendAuditEvent chains event onto the log exactly as the Postgres
+// appendAuditEvent does; callers hold mu.
+func (r *MemoryTransferRepo) appendAuditEvent(event *model.AuditEvent) {
+	prevHash := model.AuditGenesisHash
+	if n := len(r.audit); n > 0 {
+		prevHash = r.audit[n-1].Hash
+	}
+	if event.Details == "" {
+		event.Details = "{}"
+	}
+	event.ID = int64(len(r.audit) + 1)
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = prevHash
+	event.Hash = event.ComputeHash(prevHash)
+	r.audit = append(r.audit, *event)
+}
+
+func (r *MemoryTransferRepo) RecordAuditEvent(ctx context.Context, event model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appendAuditEvent(&event)
+	return nil
+}
+
+func (r *MemoryTransferRepo) ListAuditEvents(ctx context.Context, filter model.AuditFilter, beforeID int64, limit int) ([]model.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.AuditEvent
+	for i := len(r.audit) - 1; i >= 0 && len(out) < limit; i-- {
+		e := r.audit[i]
+		switch {
+		case beforeID > 0 && e.ID >= beforeID,
+			filter.ActorID > 0 && e.ActorID != filter.ActorID,
+			filter.TargetUserID > 0 && e.TargetUserID != filter.TargetUserID,
+			filter.Action != "" && e.Action != filter.Action,
+			!filter.Since.IsZero() && e.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !e.CreatedAt.Before(filter.Until):
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *MemoryTransferRepo) ScanAuditEvents(ctx context.Context, afterID int64, limit int) ([]model.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.AuditEvent
+	for _, e := range r.audit {
+		if e.ID > afterID && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// SeedUser inserts a user with a fixed ID, password hash and balance, like
+// the rows in seed.sql. Balances are not drawn from the system account.
+func (r *MemoryTransferRepo) SeedUser(user model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[user.ID]; exists {
+		return fmt.Errorf("user %d already exists", user.ID)
+	}
+	if user.Status == "" {
+		user.Status = model.StatusActive
+	}
+	if user.Roles == "" {
+		user.Roles = model.RoleUser
+	}
+	r.users[user.ID] = &user
+	if user.ID >= r.nextID {
+		r.nextID = user.ID + 1
+	}
+	return nil
+}
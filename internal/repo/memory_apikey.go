@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"project/internal/model"
+	"project/internal/service"
+)
+
+// MemoryAPIKeyRepo is the in-process counterpart of GormAPIKeyRepo.
+type MemoryAPIKeyRepo struct {
+	mu       sync.Mutex
+	accounts []model.ServiceAccount
+	keys     []model.APIKey
+}
+
+func NewMemoryAPIKeyRepo() *MemoryAPIKeyRepo {
+	return &MemoryAPIKeyRepo{}
+}
+
+func (r *MemoryAPIKeyRepo) EnsureServiceAccount(ctx context.Context, ownerID int64, name string) (model.ServiceAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.accounts {
+		if a.OwnerID == ownerID && a.Name == name {
+			return a, nil
+		}
+	}
+	a := model.ServiceAccount{ID: int64(len(r.accounts) + 1), OwnerID: ownerID, Name: name, CreatedAt: time.Now().UTC()}
+	r.accounts = append(r.accounts, a)
+	return a, nil
+}
+
+func (r *MemoryAPIKeyRepo) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Prefix == key.Prefix {
+			return errors.New("duplicate api key prefix")
+		}
+	}
+	key.ID = int64(len(r.keys) + 1)
+	key.CreatedAt = time.Now().UTC()
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+// record joins a key with its service account; callers hold mu.
+func (r *MemoryAPIKeyRepo) record(k model.APIKey) model.APIKeyRecord {
+	a := r.accounts[k.ServiceAccountID-1]
+	return model.APIKeyRecord{APIKey: k, ServiceAccountName: a.Name, OwnerID: a.OwnerID}
+}
+
+func (r *MemoryAPIKeyRepo) ListAPIKeys(ctx context.Context, ownerID int64) ([]model.APIKeyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.APIKeyRecord
+	for _, k := range r.keys {
+		if rec := r.record(k); rec.OwnerID == ownerID {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *MemoryAPIKeyRepo) FindAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKeyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Prefix == prefix {
+			return r.record(k), nil
+		}
+	}
+	return model.APIKeyRecord{}, errors.New("api key not found")
+}
+
+func (r *MemoryAPIKeyRepo) RevokeAPIKey(ctx context.Context, ownerID, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, k := range r.keys {
+		if k.ID == id && r.record(k).OwnerID == ownerID {
+			if k.RevokedAt == nil {
+				r.keys[i].RevokedAt = &at
+			}
+			return nil
+		}
+	}
+	return service.ErrAPIKeyNotFound
+}
+
+func (r *MemoryAPIKeyRepo) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, k := range r.keys {
+		if k.ID == id && (k.LastUsedAt == nil || k.LastUsedAt.Before(at.Add(-lastUsedGranularity))) {
+			r.keys[i].LastUsedAt = &at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"project/config"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+)
+
+var (
+	ErrPublishQueueFull = errors.New("publish queue is full")
+	ErrPublisherStopped = errors.New("publisher is stopped")
+)
+
+// memoryBrokerQueueSize is how many messages may wait for delivery. The
+// event relay keeps a batch in the outbox while the queue is too full for it.
+const memoryBrokerQueueSize = 10000
+
+// MemoryBroker replaces Pub/Sub inside a single process. Messages are
+// delivered one at a time in publish order, so per-key ordering holds
+// trivially. A message whose handler keeps failing is retried up to the
+// configured max delivery attempts and then kept as a dead letter.
+type MemoryBroker struct {
+	queue       chan *pubsubpb.PubsubMessage
+	maxAttempts int
+	retryDelay  time.Duration
+
+	mu      sync.RWMutex
+	started bool
+	stopped bool
+	nextID  int64
+	dead    []*pubsubpb.PubsubMessage
+	done    chan struct{}
+}
+
+func NewMemoryBroker(config *config.Config) *MemoryBroker {
+	return &MemoryBroker{
+		queue:       make(chan *pubsubpb.PubsubMessage, memoryBrokerQueueSize),
+		maxAttempts: int(config.PubSub.MaxDeliveryAttempts),
+		retryDelay:  100 * time.Millisecond,
+		done:        make(chan struct{}),
+	}
+}
+
+// PublishBatch queues msgs for delivery in order. Either all of them are
+// queued or, if the queue lacks room, none are.
+func (b *MemoryBroker) PublishBatch(msgs []Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		return ErrPublisherStopped
+	}
+	// Sends only happen under mu, so the room checked here is still free.
+	if cap(b.queue)-len(b.queue) < len(msgs) {
+		return ErrPublishQueueFull
+	}
+	for _, m := range msgs {
+		b.nextID++
+		b.queue <- &pubsubpb.PubsubMessage{
+			MessageId:   strconv.FormatInt(b.nextID, 10),
+			OrderingKey: m.OrderingKey,
+			Data:        m.Data,
+		}
+	}
+	return nil
+}
+
+// Start delivers queued messages to handle until Stop is called.
+func (b *MemoryBroker) Start(handle MessageHandler) {
+	b.mu.Lock()
+	b.started = true
+	b.mu.Unlock()
+	go func() {
+		defer close(b.done)
+		for msg := range b.queue {
+			b.deliver(handle, msg)
+		}
+	}()
+}
+
+func (b *MemoryBroker) deliver(handle MessageHandler, msg *pubsubpb.PubsubMessage) {
+	for attempt := 1; ; attempt++ {
+		err := handle(context.Background(), msg)
+		if err == nil {
+			return
+		}
+		log.Printf("[MemoryBroker] handle message %s failed (attempt %d): %v", msg.MessageId, attempt, err)
+		if attempt >= b.maxAttempts {
+			b.mu.Lock()
+			b.dead = append(b.dead, msg)
+			b.mu.Unlock()
+			log.Printf("[MemoryBroker] dead-lettered message %s", msg.MessageId)
+			return
+		}
+		time.Sleep(time.Duration(attempt) * b.retryDelay)
+	}
+}
+
+// Stop refuses new messages and waits until the queue has been delivered,
+// or ctx expires.
+func (b *MemoryBroker) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.stopped {
+		b.stopped = true
+		close(b.queue)
+	}
+	started := b.started
+	b.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DeadLetters returns the messages that exhausted their delivery attempts.
+func (b *MemoryBroker) DeadLetters() []*pubsubpb.PubsubMessage {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]*pubsubpb.PubsubMessage(nil), b.dead...)
+}
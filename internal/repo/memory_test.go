@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/service"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTransferRepo_MatchesPostgresRules(t *testing.T) {
+	r := NewMemoryTransferRepo()
+	ctx := context.Background()
+	require.NoError(t, r.SeedUser(model.User{ID: 1, Name: "alice", Balance: 100}))
+	require.NoError(t, r.SeedUser(model.User{ID: 2, Name: "bob"}))
+
+	_, err := r.InsertTransaction(ctx, 1, 2, 101, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrInsufficientFunds)
+	_, err = r.InsertTransaction(ctx, 1, 99, 1, model.AuditEvent{})
+	require.ErrorIs(t, err, &service.Error{Reason: "USER_NOT_FOUND"})
+
+	tx, err := r.InsertTransaction(ctx, 1, 2, 40, model.AuditEvent{Action: model.AuditTransfer})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, tx.ID)
+
+	_, err = r.SetAccountStatus(ctx, 2, model.StatusFrozen, "fraud check", time.Now(), model.AuditEvent{Action: model.AuditSetAccountStatus})
+	require.NoError(t, err)
+	_, err = r.InsertTransaction(ctx, 1, 2, 10, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrRecipientFrozen)
+
+	// Adjustments reach frozen accounts and draw on the system account.
+	_, balance, err := r.AdjustBalance(ctx, 2, 25, model.AuditEvent{Action: model.AuditAdjustBalance})
+	require.NoError(t, err)
+	require.EqualValues(t, 65, balance)
+	sys, err := r.GetBalance(ctx, model.SystemAccountID)
+	require.NoError(t, err)
+	require.EqualValues(t, -25, sys)
+
+	user := model.User{Name: "carol", Password: "hash"}
+	require.NoError(t, r.CreateUser(ctx, &user, 10, model.AuditEvent{Action: model.AuditCreateUser}))
+	require.EqualValues(t, 3, user.ID)
+
+	res, err := service.VerifyAuditChain(ctx, r, 2)
+	require.NoError(t, err)
+	require.Equal(t, 4, res.Checked)
+	events, err := r.ListAuditEvents(ctx, model.AuditFilter{TargetUserID: 3}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Contains(t, events[0].Details, "transaction_id")
+}
+
+func TestMemoryTokenStore_Expiry(t *testing.T) {
+	s := NewMemoryTokenStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveToken(ctx, 1, "tok", time.Minute))
+	got, err := s.GetToken(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "tok", got)
+
+	claimed, err := s.ClaimEvent(ctx, "e1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	claimed, err = s.ClaimEvent(ctx, "e1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	now = now.Add(time.Minute)
+	got, err = s.GetToken(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	claimed, err = s.ClaimEvent(ctx, "e1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed, "an expired lease can be claimed again")
+}
+
+func TestMemoryBroker_RetriesThenDeadLetters(t *testing.T) {
+	cfg := &config.Config{PubSub: config.PubSubConfig{MaxDeliveryAttempts: 3}}
+	b := NewMemoryBroker(cfg)
+	b.retryDelay = time.Millisecond
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	var delivered []string
+	b.Start(func(ctx context.Context, msg *pubsubpb.PubsubMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[string(msg.Data)]++
+		if string(msg.Data) == "poison" {
+			return errors.New("cannot handle")
+		}
+		delivered = append(delivered, string(msg.Data))
+		return nil
+	})
+
+	require.NoError(t, b.PublishBatch([]Message{{OrderingKey: "k", Data: []byte("a")}, {OrderingKey: "k", Data: []byte("poison")}}))
+	require.NoError(t, b.PublishBatch([]Message{{OrderingKey: "k", Data: []byte("b")}}))
+	require.NoError(t, b.Stop(context.Background()))
+	require.ErrorIs(t, b.PublishBatch([]Message{{OrderingKey: "k", Data: []byte("late")}}), ErrPublisherStopped)
+
+	require.Equal(t, []string{"a", "b"}, delivered)
+	require.Equal(t, 3, attempts["poison"])
+	require.Len(t, b.DeadLetters(), 1)
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryTokenStore keeps sessions and consumer dedup state in process. It
+// satisfies the same interfaces as the Redis client, with the same expiry
+// semantics.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	values map[string]memoryValue
+}
+
+type memoryValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{now: time.Now, values: map[string]memoryValue{}}
+}
+
+// get returns the live value for key; callers hold mu.
+func (s *MemoryTokenStore) get(key string) (string, bool) {
+	v, ok := s.values[key]
+	if !ok {
+		return "", false
+	}
+	if !v.expiresAt.IsZero() && !s.now().Before(v.expiresAt) {
+		delete(s.values, key)
+		return "", false
+	}
+	return v.value, true
+}
+
+// set stores value under key; a zero ttl never expires. Callers hold mu.
+func (s *MemoryTokenStore) set(key, value string, ttl time.Duration) {
+	v := memoryValue{value: value}
+	if ttl > 0 {
+		v.expiresAt = s.now().Add(ttl)
+	}
+	s.values[key] = v
+}
+
+func (s *MemoryTokenStore) SaveToken(ctx context.Context, userID int64, token string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(buildTokenKey(userID), token, ttl)
+	return nil
+}
+
+func (s *MemoryTokenStore) DeleteToken(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, buildTokenKey(userID))
+	return nil
+}
+
+func (s *MemoryTokenStore) GetToken(ctx context.Context, userID int64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, _ := s.get(buildTokenKey(userID))
+	return token, nil
+}
+
+func (s *MemoryTokenStore) ClaimEvent(ctx context.Context, eventID string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(buildEventKey(eventID)); ok {
+		return false, nil
+	}
+	s.set(buildEventKey(eventID), eventStateProcessing, ttl)
+	return true, nil
+}
+
+func (s *MemoryTokenStore) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, _ := s.get(buildEventKey(eventID))
+	return v == eventStateDone, nil
+}
+
+func (s *MemoryTokenStore) MarkEventProcessed(ctx context.Context, eventID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(buildEventKey(eventID), eventStateDone, ttl)
+	return nil
+}
+
+func (s *MemoryTokenStore) ReleaseEvent(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, buildEventKey(eventID))
+	return nil
+}
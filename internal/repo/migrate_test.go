@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := embeddedMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		require.EqualValues(t, i+1, m.Version, "migration versions must be contiguous")
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	_, err := LoadMigrations(fstest.MapFS{
+		"0001_init.up.sql": {Data: []byte("CREATE TABLE a ();")},
+	})
+	require.ErrorContains(t, err, "needs both")
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"notes.txt": {Data: []byte("x")},
+	})
+	require.ErrorContains(t, err, "unexpected file")
+
+	migrations, err := LoadMigrations(fstest.MapFS{
+		"0002_b.up.sql":   {Data: []byte("up b")},
+		"0002_b.down.sql": {Data: []byte("down b")},
+		"0001_a.up.sql":   {Data: []byte("up a")},
+		"0001_a.down.sql": {Data: []byte("down a")},
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	require.Equal(t, "a", migrations[0].Name)
+	require.Equal(t, "down b", migrations[1].Down)
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_a.up.sql"), []byte("-- a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_a.down.sql"), []byte("-- a"), 0o644))
+
+	up, down, err := CreateMigration(dir, "Add Payouts table")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "0002_add_payouts_table.up.sql"), up)
+	require.Equal(t, filepath.Join(dir, "0002_add_payouts_table.down.sql"), down)
+
+	_, _, err = CreateMigration(dir, "--")
+	require.Error(t, err)
+}
+
+// The models AutoMigrate created tables from before migrations existed.
+type legacyUser struct {
+	ID       int64  `gorm:"primaryKey;autoIncrement"`
+	Name     string `gorm:"not null"`
+	Balance  int64  `gorm:"not null;default:0"`
+	Password string `gorm:"not null"`
+}
+
+func (legacyUser) TableName() string { return "users" }
+
+type legacyTransaction struct {
+	ID     uint  `gorm:"primaryKey;autoIncrement"`
+	From   int64 `gorm:"column:from_user;not null"`
+	To     int64 `gorm:"column:to_user;not null"`
+	Amount int64 `gorm:"not null"`
+}
+
+func (legacyTransaction) TableName() string { return "transactions" }
+
+func TestMigrateAdoptsAutoMigrateSchema(t *testing.T) {
+	sqlDB, err := openTestDB(t).DB()
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// One connection, so search_path applies to everything below.
+	sqlDB.SetMaxOpenConns(1)
+	schema := fmt.Sprintf("adopt_%d", time.Now().UnixNano())
+	_, err = sqlDB.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	_, err = sqlDB.ExecContext(ctx, "SET search_path TO "+schema)
+	require.NoError(t, err)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&legacyUser{}, &legacyTransaction{}))
+	alice, bob := legacyUser{Name: "alice", Balance: 70, Password: "!"}, legacyUser{Name: "bob", Balance: 30, Password: "!"}
+	require.NoError(t, db.Create(&alice).Error)
+	require.NoError(t, db.Create(&bob).Error)
+	require.NoError(t, db.Create(&legacyTransaction{From: alice.ID, To: bob.ID, Amount: 30}).Error)
+
+	migrator, err := NewMigrator(sqlDB)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+
+	var missing int64
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM transactions WHERE created_at IS NULL").Scan(&missing).Error)
+	require.Zero(t, missing)
+	var fks []string
+	require.NoError(t, db.Raw(`SELECT conname FROM pg_constraint
+		WHERE conrelid = 'transactions'::regclass AND contype = 'f' ORDER BY conname`).Scan(&fks).Error)
+	require.Equal(t, []string{"fk_from_user", "fk_to_user"}, fks)
+	require.Error(t, db.Exec("INSERT INTO transactions (from_user, to_user, amount) VALUES (?, 999999, 1)", alice.ID).Error)
+
+	// Running it again is a no-op.
+	applied, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+}
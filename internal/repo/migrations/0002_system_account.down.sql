@@ -0,0 +1,4 @@
+-- Leave the account in place once money has moved through it.
+DELETE FROM users
+WHERE id = 0
+  AND NOT EXISTS (SELECT 1 FROM transactions WHERE from_user = 0 OR to_user = 0);
@@ -0,0 +1,5 @@
+-- Admin adjustments are posted against this account. '!' is not a valid
+-- bcrypt hash, so it can never log in.
+INSERT INTO users (id, name, balance, password, roles, status)
+VALUES (0, 'system', 0, '!', 'system', 'active')
+ON CONFLICT (id) DO NOTHING;
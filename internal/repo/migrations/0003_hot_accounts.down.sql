@@ -0,0 +1,8 @@
+-- Fold unswept credits back into their accounts before dropping the buckets.
+UPDATE users u
+SET balance = u.balance + b.total
+FROM (SELECT user_id, SUM(balance) AS total FROM account_buckets GROUP BY user_id) b
+WHERE u.id = b.user_id;
+
+DROP TABLE IF EXISTS account_buckets;
+ALTER TABLE users DROP COLUMN IF EXISTS hot_buckets;
@@ -0,0 +1,13 @@
+-- Hot accounts (hot_buckets > 0) take credits into one of their
+-- account_buckets rows, chosen at random, instead of the users row, so
+-- concurrent transfers to a busy merchant do not queue on one row lock. A
+-- sweeper folds buckets back into users.balance; the account's balance is
+-- always users.balance plus its buckets.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hot_buckets INT NOT NULL DEFAULT 0 CHECK (hot_buckets >= 0);
+
+CREATE TABLE IF NOT EXISTS account_buckets (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bucket INT NOT NULL,
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    PRIMARY KEY (user_id, bucket)
+);
@@ -0,0 +1,10 @@
+-- Queued events would be lost; let the server's audit chainer drain them first.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM audit_pending) THEN
+        RAISE EXCEPTION 'audit_pending is not empty; start the server to chain the queued events, then retry';
+    END IF;
+END;
+$$;
+
+DROP TABLE IF EXISTS audit_pending;
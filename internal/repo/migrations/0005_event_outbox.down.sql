@@ -0,0 +1,10 @@
+-- Unpublished messages would be lost; let the server's event relay drain them first.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM event_outbox) THEN
+        RAISE EXCEPTION 'event_outbox is not empty; start the server to publish the queued events, then retry';
+    END IF;
+END;
+$$;
+
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,9 @@
+-- Transfers write their Pub/Sub messages here in their own transaction. The
+-- event relay publishes them in id order and deletes them once Pub/Sub has
+-- accepted them, so a committed transfer is never left unpublished.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    ordering_key TEXT NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
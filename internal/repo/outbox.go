@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"time"
+
+	"project/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// outboxRelayLock is held by the replica relaying the outbox, so messages
+// reach Pub/Sub in id order however many replicas run the relay.
+const outboxRelayLock int64 = 0x6f7574626f785f72
+
+var eventsRelayed = expvar.NewInt("outbox_events_relayed")
+
+// outboxMessage is a row of event_outbox.
+type outboxMessage struct {
+	ID          int64
+	OrderingKey string
+	Data        []byte
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (outboxMessage) TableName() string { return "event_outbox" }
+
+// TransferCompletedMessages returns tx's TransferCompleted event once for
+// every account it touches, keyed by that account.
+func TransferCompletedMessages(tx model.Transaction) ([]Message, error) {
+	accounts := []int64{tx.From}
+	if tx.To != tx.From {
+		accounts = append(accounts, tx.To)
+	}
+	msgs := make([]Message, 0, len(accounts))
+	for _, account := range accounts {
+		event, err := model.NewTransferCompletedEvent(tx, account)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := newMessage(model.AccountOrderingKey(account), event)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// transferMessages returns the messages for txs, which moveMoney created for
+// one sender: every transaction's TransferCompleted events, then for a batch
+// the summary on the sender's stream, after its lines.
+func transferMessages(t transfer, txs []model.Transaction) ([]Message, error) {
+	var msgs []Message
+	for _, tx := range txs {
+		m, err := TransferCompletedMessages(tx)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m...)
+	}
+	if !t.batch {
+		return msgs, nil
+	}
+	event, err := model.NewBatchTransferCompletedEvent(t.from, txs)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := newMessage(model.AccountOrderingKey(t.from), event)
+	if err != nil {
+		return nil, err
+	}
+	return append(msgs, msg), nil
+}
+
+func newMessage(orderingKey string, event model.Event) (Message, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{OrderingKey: orderingKey, Data: data}, nil
+}
+
+// writeOutbox adds the messages for txs to the outbox in tx's transaction.
+func writeOutbox(tx *gorm.DB, t transfer, txs []model.Transaction) error {
+	msgs, err := transferMessages(t, txs)
+	if err != nil {
+		return err
+	}
+	rows := make([]outboxMessage, len(msgs))
+	for i, m := range msgs {
+		rows[i] = outboxMessage{OrderingKey: m.OrderingKey, Data: m.Data}
+	}
+	return tx.Create(&rows).Error
+}
+
+// RelayEvents hands up to limit outbox messages to send, in id order, and
+// deletes them once send returns nil. It returns how many it relayed: 0 when
+// the outbox is empty or another replica is relaying. If send fails the
+// messages stay at the head of the outbox for the next call, so nothing is
+// lost and nothing overtakes them.
+func (r *GormTransferRepo) RelayEvents(ctx context.Context, limit int, send func(msgs []Message) error) (int, error) {
+	var relayed int
+	err := r.transaction(ctx, "RelayEvents", func(tx *gorm.DB) error {
+		relayed = 0
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLock).Row().Scan(&locked); err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		var rows []outboxMessage
+		if err := tx.Order("id").Limit(limit).Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		msgs := make([]Message, len(rows))
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			msgs[i] = Message{OrderingKey: row.OrderingKey, Data: row.Data}
+			ids[i] = row.ID
+		}
+		if err := send(msgs); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM event_outbox WHERE id IN ?", ids).Error; err != nil {
+			return err
+		}
+		relayed = len(rows)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	eventsRelayed.Add(int64(relayed))
+	return relayed, nil
+}
@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"project/config"
+	"project/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+// drainOutbox relays everything queued so far and returns the decoded events
+// on each account's stream, in publish order.
+func drainOutbox(t *testing.T, relay func(ctx context.Context, limit int, send func([]Message) error) (int, error)) map[string][]model.Event {
+	t.Helper()
+	streams := map[string][]model.Event{}
+	for {
+		n, err := relay(context.Background(), 100, func(msgs []Message) error {
+			for _, m := range msgs {
+				event, err := model.DecodeEvent(m.Data)
+				if err != nil {
+					return err
+				}
+				streams[m.OrderingKey] = append(streams[m.OrderingKey], event)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		if n == 0 {
+			return streams
+		}
+	}
+}
+
+func TestRelayEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransferRepo(db, config.LoadConfig())
+	ctx := context.Background()
+	ids := createTestUsers(t, db, 100, 0)
+	from, to := ids[0], ids[1]
+	drainOutbox(t, repo.RelayEvents)
+
+	tx, err := repo.InsertTransaction(ctx, from, to, 10, model.AuditEvent{})
+	require.NoError(t, err)
+	batch, err := repo.InsertTransactions(ctx, from, []model.TransferLine{{To: to, Amount: 5}}, model.AuditEvent{})
+	require.NoError(t, err)
+	// A failed transfer writes nothing.
+	_, err = repo.InsertTransaction(ctx, from, to, 1000, model.AuditEvent{})
+	require.Error(t, err)
+
+	// A failed send leaves the messages in place.
+	n, err := repo.RelayEvents(ctx, 100, func([]Message) error { return errors.New("broker down") })
+	require.Error(t, err)
+	require.Zero(t, n)
+
+	streams := drainOutbox(t, repo.RelayEvents)
+	sender, recipient := streams[model.AccountOrderingKey(from)], streams[model.AccountOrderingKey(to)]
+	require.Len(t, sender, 3)
+	require.Equal(t, model.EventTransferCompleted, sender[0].Type)
+	require.Equal(t, model.EventTransferCompleted, sender[1].Type)
+	require.Equal(t, model.EventBatchTransferCompleted, sender[2].Type)
+	require.Equal(t, model.BatchID(batch), sender[2].ID)
+	require.Len(t, recipient, 2)
+	require.Equal(t, to, recipient[0].Account)
+	require.NotEqual(t, sender[0].ID, recipient[0].ID)
+
+	var data model.TransferCompleted
+	require.NoError(t, json.Unmarshal(recipient[0].Data, &data))
+	require.Equal(t, tx.ID, data.TransactionID)
+}
+
+// TestRelayEvents_AccountStreamInTransactionOrder runs transfers in both
+// directions between two accounts from many goroutines. Each account's stream
+// must carry its transactions in ID order, as a consumer replaying it to a
+// balance needs.
+func TestRelayEvents_AccountStreamInTransactionOrder(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransferRepo(db, config.LoadConfig())
+	ctx := context.Background()
+	ids := createTestUsers(t, db, 1000, 1000)
+	a, b := ids[0], ids[1]
+	drainOutbox(t, repo.RelayEvents)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			from, to := a, b
+			if i%2 == 1 {
+				from, to = b, a
+			}
+			for j := 0; j < 10; j++ {
+				if _, err := repo.InsertTransaction(ctx, from, to, 1, model.AuditEvent{}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	streams := drainOutbox(t, repo.RelayEvents)
+	for _, account := range []int64{a, b} {
+		stream := streams[model.AccountOrderingKey(account)]
+		require.Len(t, stream, 200)
+		var last uint
+		for _, event := range stream {
+			var data model.TransferCompleted
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			require.Greater(t, data.TransactionID, last, "account %d", account)
+			last = data.TransactionID
+		}
+	}
+}
@@ -2,9 +2,11 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"project/config"
+	"sync"
 	"time"
 
 	pubsub "cloud.google.com/go/pubsub/apiv1"
@@ -12,7 +14,16 @@ import (
 
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// pullRetryDelay is how long Subscribe waits after a failed pull.
+	pullRetryDelay = time.Second
+	// settleTimeout bounds acking or nacking a handled batch.
+	settleTimeout = 10 * time.Second
 )
 
 type PubSub struct {
@@ -39,6 +50,7 @@ func NewPubSubClient(config *config.Config) (*PubSub, error) {
 	// Subscriber client
 	subClient, err := pubsub.NewSubscriberClient(ctx, opts...)
 	if err != nil {
+		pubClient.Close()
 		return nil, fmt.Errorf("failed to create pubsub subscriber client: %w", err)
 	}
 
@@ -48,25 +60,49 @@ func NewPubSubClient(config *config.Config) (*PubSub, error) {
 		config:    config,
 	}, nil
 }
+
+// Close closes the publisher and subscriber connections.
+func (p *PubSub) Close() error {
+	return errors.Join(p.pubClient.Close(), p.subClient.Close())
+}
+
 func (p *PubSub) Hello(msg string) error {
 	fmt.Println("Hello from PubSub:", msg)
 	return nil
 }
 
-func (p *PubSub) Publish(data []byte) error {
-	topicPath := fmt.Sprintf("projects/%s/topics/%s",
-		p.config.PubSub.ProjectID, p.config.PubSub.Topic)
+// Publish sends data to the main topic. Messages sharing an orderingKey are
+// delivered in publish order to subscriptions with message ordering enabled.
+func (p *PubSub) Publish(orderingKey string, data []byte) error {
+	return p.publish(p.topicPath(p.config.PubSub.Topic), &pubsubpb.PubsubMessage{
+		Data:        data,
+		OrderingKey: orderingKey,
+	})
+}
+
+type Message struct {
+	OrderingKey string
+	Data        []byte
+}
+
+// PublishBatch sends msgs to the main topic in a single request.
+func (p *PubSub) PublishBatch(msgs []Message) error {
+	batch := make([]*pubsubpb.PubsubMessage, 0, len(msgs))
+	for _, m := range msgs {
+		batch = append(batch, &pubsubpb.PubsubMessage{Data: m.Data, OrderingKey: m.OrderingKey})
+	}
+	return p.publish(p.topicPath(p.config.PubSub.Topic), batch...)
+}
 
+func (p *PubSub) publish(topicPath string, msgs ...*pubsubpb.PubsubMessage) error {
 	var lastErr error
 	for i := 0; i < 3; i++ {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		resp, err := p.pubClient.Publish(ctx, &pubsubpb.PublishRequest{
-			Topic: topicPath,
-			Messages: []*pubsubpb.PubsubMessage{
-				{Data: data},
-			},
+			Topic:    topicPath,
+			Messages: msgs,
 		})
 		if err == nil {
 			fmt.Printf("[PubSub v2] Published message IDs: %v\n", resp.MessageIds)
@@ -79,19 +115,37 @@ func (p *PubSub) Publish(data []byte) error {
 	return fmt.Errorf("failed to publish after retries: %w", lastErr)
 }
 
-func (p *PubSub) Subscribe(ctx context.Context) error {
-	subPath := fmt.Sprintf("projects/%s/subscriptions/%s",
-		p.config.PubSub.ProjectID, p.config.PubSub.Subcription)
-	nctx := context.Background()
+func (p *PubSub) topicPath(topic string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", p.config.PubSub.ProjectID, topic)
+}
+
+func (p *PubSub) subscriptionPath(sub string) string {
+	return fmt.Sprintf("projects/%s/subscriptions/%s", p.config.PubSub.ProjectID, sub)
+}
+
+// MessageHandler processes a single message. Returning an error nacks the
+// message so Pub/Sub redelivers it and, once the subscription's max delivery
+// attempts are exhausted, forwards it to the dead-letter topic.
+type MessageHandler func(ctx context.Context, msg *pubsubpb.PubsubMessage) error
+
+func (p *PubSub) Subscribe(ctx context.Context, handle MessageHandler) error {
+	subPath := p.subscriptionPath(p.config.PubSub.Subcription)
 	log.Println("Starting PubSub consumer...")
 	for {
-
-		resp, err := p.subClient.Pull(nctx, &pubsubpb.PullRequest{
+		resp, err := p.subClient.Pull(ctx, &pubsubpb.PullRequest{
 			Subscription: subPath,
 			MaxMessages:  100,
 		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
-			fmt.Println(err)
+			log.Printf("[PubSub v2] Pull error: %v", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pullRetryDelay):
+			}
 			continue
 		}
 
@@ -99,20 +153,119 @@ func (p *PubSub) Subscribe(ctx context.Context) error {
 			continue
 		}
 
-		ackIDs := make([]string, 0, len(resp.ReceivedMessages))
-		for _, m := range resp.ReceivedMessages {
-			log.Printf("[PubSub v2] Received message: %s", string(m.Message.Data))
-			ackIDs = append(ackIDs, m.AckId)
+		ackIDs, nackIDs := p.handleBatch(ctx, resp.ReceivedMessages, handle)
+
+		// Settle the batch even if ctx was canceled while handling it, so
+		// processed messages are not redelivered.
+		settleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+		if len(ackIDs) > 0 {
+			if err := p.subClient.Acknowledge(settleCtx, &pubsubpb.AcknowledgeRequest{
+				Subscription: subPath,
+				AckIds:       ackIDs,
+			}); err != nil {
+				log.Printf("[PubSub v2] Ack error: %v", err)
+			} else {
+				log.Printf("[PubSub v2] Ack success: %d message(s)", len(ackIDs))
+			}
 		}
 
-		if err := p.subClient.Acknowledge(nctx, &pubsubpb.AcknowledgeRequest{
-			Subscription: subPath,
-			AckIds:       ackIDs,
-		}); err != nil {
-			log.Printf("[PubSub v2] Ack error: %v", err)
-		} else {
-			log.Printf("[PubSub v2] Ack success: %d message(s)", len(ackIDs))
+		if len(nackIDs) > 0 {
+			if err := p.nack(settleCtx, subPath, nackIDs); err != nil {
+				log.Printf("[PubSub v2] Nack error: %v", err)
+			}
 		}
+		cancel()
+	}
+}
+
+// handleBatch processes messages that share an ordering key one after another
+// and different keys in parallel. Once a message fails, the rest of its key's
+// batch is nacked without being handled: Pub/Sub redelivers the failed message
+// and everything after it for that key, so processing resumes in order.
+func (p *PubSub) handleBatch(ctx context.Context, msgs []*pubsubpb.ReceivedMessage, handle MessageHandler) (ackIDs, nackIDs []string) {
+	var groups [][]*pubsubpb.ReceivedMessage
+	byKey := make(map[string]int)
+	for _, m := range msgs {
+		key := m.Message.OrderingKey
+		if key == "" {
+			groups = append(groups, []*pubsubpb.ReceivedMessage{m})
+			continue
+		}
+		i, ok := byKey[key]
+		if !ok {
+			i = len(groups)
+			byKey[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], m)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group []*pubsubpb.ReceivedMessage) {
+			defer wg.Done()
+			var acks, nacks []string
+			for i, m := range group {
+				log.Printf("[PubSub v2] Received message: %s", string(m.Message.Data))
+				if err := handle(ctx, m.Message); err != nil {
+					log.Printf("[PubSub v2] Handle message %s failed (attempt %d): %v", m.Message.MessageId, m.DeliveryAttempt, err)
+					for _, rest := range group[i:] {
+						nacks = append(nacks, rest.AckId)
+					}
+					break
+				}
+				acks = append(acks, m.AckId)
+			}
+			mu.Lock()
+			ackIDs = append(ackIDs, acks...)
+			nackIDs = append(nackIDs, nacks...)
+			mu.Unlock()
+		}(group)
+	}
+	wg.Wait()
+	return ackIDs, nackIDs
+}
 
+// EnsureSubscription creates the consumer subscription with message ordering
+// and the dead-letter policy if it does not exist yet. Ordering cannot be
+// turned on for an existing subscription, so that case is only reported.
+func (p *PubSub) EnsureSubscription(ctx context.Context) error {
+	subPath := p.subscriptionPath(p.config.PubSub.Subcription)
+	sub, err := p.subClient.GetSubscription(ctx, &pubsubpb.GetSubscriptionRequest{Subscription: subPath})
+	if err == nil {
+		if !sub.EnableMessageOrdering {
+			log.Printf("[PubSub v2] subscription %s has message ordering disabled; recreate it to get per-account ordering", subPath)
+		}
+		return nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return fmt.Errorf("get subscription: %w", err)
+	}
+
+	req := &pubsubpb.Subscription{
+		Name:                  subPath,
+		Topic:                 p.topicPath(p.config.PubSub.Topic),
+		EnableMessageOrdering: true,
+	}
+	if p.config.PubSub.DeadLetterTopic != "" {
+		req.DeadLetterPolicy = &pubsubpb.DeadLetterPolicy{
+			DeadLetterTopic:     p.topicPath(p.config.PubSub.DeadLetterTopic),
+			MaxDeliveryAttempts: p.config.PubSub.MaxDeliveryAttempts,
+		}
+	}
+	if _, err := p.subClient.CreateSubscription(ctx, req); err != nil && status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf("create subscription: %w", err)
 	}
+	log.Printf("[PubSub v2] created ordered subscription %s", subPath)
+	return nil
+}
+
+func (p *PubSub) nack(ctx context.Context, subPath string, ackIDs []string) error {
+	return p.subClient.ModifyAckDeadline(ctx, &pubsubpb.ModifyAckDeadlineRequest{
+		Subscription:       subPath,
+		AckIds:             ackIDs,
+		AckDeadlineSeconds: 0,
+	})
 }
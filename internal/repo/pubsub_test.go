@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"project/config"
+
+	pubsubpb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+	"github.com/stretchr/testify/require"
+)
+
+func received(ackID, key string) *pubsubpb.ReceivedMessage {
+	return &pubsubpb.ReceivedMessage{
+		AckId:   ackID,
+		Message: &pubsubpb.PubsubMessage{MessageId: ackID, OrderingKey: key},
+	}
+}
+
+func TestHandleBatch_OrderedPerKey(t *testing.T) {
+	msgs := []*pubsubpb.ReceivedMessage{
+		received("a1", "account-1"),
+		received("b1", "account-2"),
+		received("a2", "account-1"),
+		received("n1", ""),
+		received("a3", "account-1"),
+		received("b2", "account-2"),
+	}
+
+	var mu sync.Mutex
+	seen := map[string][]string{}
+	handle := func(ctx context.Context, msg *pubsubpb.PubsubMessage) error {
+		mu.Lock()
+		seen[msg.OrderingKey] = append(seen[msg.OrderingKey], msg.MessageId)
+		mu.Unlock()
+		if msg.MessageId == "a2" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	ackIDs, nackIDs := (&PubSub{}).handleBatch(context.Background(), msgs, handle)
+	sort.Strings(ackIDs)
+	sort.Strings(nackIDs)
+
+	require.Equal(t, []string{"a1", "b1", "b2", "n1"}, ackIDs)
+	require.Equal(t, []string{"a2", "a3"}, nackIDs, "messages after a failure must be redelivered with it")
+	require.Equal(t, []string{"a1", "a2"}, seen["account-1"], "a3 must not be handled after a2 failed")
+	require.Equal(t, []string{"b1", "b2"}, seen["account-2"])
+}
+
+func TestSubscribe_ReturnsWhenContextCanceled(t *testing.T) {
+	// Nothing listens on port 1, so every pull fails.
+	ps, err := NewPubSubClient(&config.Config{PubSub: config.PubSubConfig{Endpoint: "127.0.0.1:1", ProjectID: "test", Subcription: "sub"}})
+	require.NoError(t, err)
+	defer ps.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Subscribe(ctx, func(context.Context, *pubsubpb.PubsubMessage) error { return nil })
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe did not return after its context was canceled")
+	}
+}
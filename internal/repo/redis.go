@@ -53,3 +53,35 @@ func (s *redisClient) GetToken(ctx context.Context, userID int64) (string, error
 	}
 	return val, nil
 }
+
+const (
+	eventStateProcessing = "processing"
+	eventStateDone       = "done"
+)
+
+func buildEventKey(eventID string) string {
+	return "events:processed:" + eventID
+}
+
+func (s *redisClient) ClaimEvent(ctx context.Context, eventID string, ttl time.Duration) (bool, error) {
+	return s.rdb.SetNX(ctx, buildEventKey(eventID), eventStateProcessing, ttl).Result()
+}
+
+func (s *redisClient) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
+	val, err := s.rdb.Get(ctx, buildEventKey(eventID)).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return val == eventStateDone, nil
+}
+
+func (s *redisClient) MarkEventProcessed(ctx context.Context, eventID string, ttl time.Duration) error {
+	return s.rdb.Set(ctx, buildEventKey(eventID), eventStateDone, ttl).Err()
+}
+
+func (s *redisClient) ReleaseEvent(ctx context.Context, eventID string) error {
+	return s.rdb.Del(ctx, buildEventKey(eventID)).Err()
+}
@@ -0,0 +1,22 @@
+// Package repotest seeds the in-memory repositories for tests in other
+// packages.
+package repotest
+
+import (
+	"testing"
+
+	"project/internal/model"
+	"project/internal/repo"
+
+	"github.com/stretchr/testify/require"
+)
+
+// NewTransferRepo returns a MemoryTransferRepo holding users.
+func NewTransferRepo(t testing.TB, users ...model.User) *repo.MemoryTransferRepo {
+	t.Helper()
+	store := repo.NewMemoryTransferRepo()
+	for _, u := range users {
+		require.NoError(t, store.SeedUser(u))
+	}
+	return store
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"project/config"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// SQLSTATEs after which the whole transaction can safely run again.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+	sqlStateLockNotAvailable     = "55P03"
+)
+
+var (
+	// txRetries counts retried transactions by SQLSTATE.
+	txRetries          = expvar.NewMap("db_tx_retries")
+	txRetriesExhausted = expvar.NewInt("db_tx_retries_exhausted")
+)
+
+// retryPolicy bounds how transfer transactions wait and retry. The zero value
+// makes a single attempt with the server's default timeouts.
+type retryPolicy struct {
+	MaxAttempts      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockTimeout      time.Duration
+	StatementTimeout time.Duration
+}
+
+func newRetryPolicy(cfg config.DatabaseConfig) retryPolicy {
+	return retryPolicy{
+		MaxAttempts:      cfg.TxMaxAttempts,
+		BaseDelay:        cfg.TxRetryBaseDelay,
+		MaxDelay:         cfg.TxRetryMaxDelay,
+		LockTimeout:      cfg.LockTimeout,
+		StatementTimeout: cfg.StatementTimeout,
+	}
+}
+
+// retryableSQLState returns the SQLSTATE of err when retrying the transaction
+// may succeed.
+func retryableSQLState(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+	switch pgErr.Code {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected, sqlStateLockNotAvailable:
+		return pgErr.Code, true
+	}
+	return "", false
+}
+
+// backoff returns a random delay of up to BaseDelay*2^(attempt-1), capped at
+// MaxDelay, to wait after the given failed attempt.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (p.MaxDelay > 0 && ceiling > p.MaxDelay) {
+		ceiling = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retry runs fn until it succeeds, fails with an error that is not
+// retryable, or MaxAttempts is used up. fn must be safe to run again.
+func (p retryPolicy) retry(ctx context.Context, op string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		code, ok := retryableSQLState(err)
+		if !ok {
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			txRetriesExhausted.Add(1)
+			log.Printf("[Repo] %s gave up after %d attempt(s): %v", op, attempt, err)
+			return err
+		}
+		txRetries.Add(code, 1)
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// transaction runs fn in a transaction with the policy's lock and statement
+// timeouts, retrying it on serialization failures, deadlocks and lock
+// timeouts.
+func (r *GormTransferRepo) transaction(ctx context.Context, op string, fn func(tx *gorm.DB) error) error {
+	return r.retry.retry(ctx, op, func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := setLocalTimeouts(tx, r.retry.LockTimeout, r.retry.StatementTimeout); err != nil {
+				return err
+			}
+			return fn(tx)
+		})
+	})
+}
+
+func setLocalTimeouts(tx *gorm.DB, lock, statement time.Duration) error {
+	if lock <= 0 && statement <= 0 {
+		return nil
+	}
+	return tx.Exec("SELECT set_config('lock_timeout', ?, true), set_config('statement_timeout', ?, true)",
+		pgMillis(lock), pgMillis(statement)).Error
+}
+
+// pgMillis formats d as a Postgres duration setting; 0 disables the timeout.
+func pgMillis(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"project/internal/service"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryableSQLState(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+		ok   bool
+	}{
+		{err: &pgconn.PgError{Code: "40001"}, code: "40001", ok: true},
+		{err: fmt.Errorf("lock user: %w", &pgconn.PgError{Code: "40P01"}), code: "40P01", ok: true},
+		{err: &pgconn.PgError{Code: "55P03"}, code: "55P03", ok: true},
+		{err: &pgconn.PgError{Code: "57014"}}, // statement timeout
+		{err: &pgconn.PgError{Code: "23505"}},
+		{err: service.ErrInsufficientFunds},
+		{err: nil},
+	}
+	for _, tt := range tests {
+		code, ok := retryableSQLState(tt.err)
+		require.Equal(t, tt.ok, ok, "%v", tt.err)
+		require.Equal(t, tt.code, code, "%v", tt.err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := retryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 35 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		require.LessOrEqual(t, p.backoff(1), 10*time.Millisecond)
+		require.LessOrEqual(t, p.backoff(2), 20*time.Millisecond)
+		require.LessOrEqual(t, p.backoff(3), 35*time.Millisecond)
+		require.LessOrEqual(t, p.backoff(70), 35*time.Millisecond)
+		require.GreaterOrEqual(t, p.backoff(70), time.Duration(0))
+	}
+	require.Zero(t, retryPolicy{}.backoff(3))
+}
+
+func TestRetryPolicy_Retry(t *testing.T) {
+	deadlock := &pgconn.PgError{Code: "40P01"}
+	p := retryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		errs      []error // returned by successive attempts; nil after the list ends
+		attempts  int
+		wantErr   error
+		exhausted int64
+	}{
+		{name: "first try", attempts: 1},
+		{name: "recovers", errs: []error{deadlock, &pgconn.PgError{Code: "40001"}}, attempts: 3},
+		{name: "not retryable", errs: []error{service.ErrInsufficientFunds}, attempts: 1, wantErr: service.ErrInsufficientFunds},
+		{name: "budget used up", errs: []error{deadlock, deadlock, deadlock, deadlock}, attempts: 3, wantErr: deadlock, exhausted: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exhaustedBefore := txRetriesExhausted.Value()
+			attempts := 0
+			err := p.retry(context.Background(), "test", func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			require.Equal(t, tt.attempts, attempts)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.exhausted, txRetriesExhausted.Value()-exhaustedBefore)
+		})
+	}
+
+	// A cancelled context stops the backoff.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err := retryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}.retry(ctx, "test", func() error {
+		attempts++
+		return deadlock
+	})
+	require.ErrorIs(t, err, deadlock)
+	require.Equal(t, 1, attempts)
+}
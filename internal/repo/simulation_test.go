@@ -0,0 +1,327 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+// simulationRepo is the part of the transfer repository the simulation drives.
+type simulationRepo interface {
+	InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error)
+	InsertTransactions(ctx context.Context, from int64, lines []model.TransferLine, event model.AuditEvent) ([]model.Transaction, error)
+	GetBalance(ctx context.Context, userID int64) (int64, error)
+	ListUserTransactions(ctx context.Context, userID int64, beforeID uint, limit int) ([]model.Transaction, error)
+	SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error
+	SweepHotAccounts(ctx context.Context) (int, error)
+}
+
+type simulationConfig struct {
+	Seed        int64
+	Accounts    int
+	HotAccounts int // the first HotAccounts accounts take credits into buckets
+	Balance     int64
+	Transfers   int
+	Workers     int
+}
+
+// simulationTransfer is one planned transfer, or a batch when it has more
+// than one line. Plans depend only on the seed, so a failing run can be
+// replayed; the interleaving of workers cannot.
+type simulationTransfer struct {
+	From  int // index into the account list
+	Lines []simulationLine
+}
+
+type simulationLine struct {
+	To     int // index into the account list
+	Amount int64
+}
+
+func newSimulationConfig(t *testing.T) simulationConfig {
+	cfg := simulationConfig{Seed: time.Now().UnixNano(), Accounts: 40, HotAccounts: 4, Balance: 1000, Transfers: 5000, Workers: 16}
+	if testing.Short() {
+		cfg.Transfers = 500
+	}
+	if v := os.Getenv("TEST_SIM_SEED"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		require.NoError(t, err, "TEST_SIM_SEED")
+		cfg.Seed = seed
+	}
+	if v := os.Getenv("TEST_SIM_TRANSFERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		require.NoError(t, err, "TEST_SIM_TRANSFERS")
+		cfg.Transfers = n
+	}
+	return cfg
+}
+
+// plan draws the transfers; about one in ten is a batch of two to eight
+// lines. Amounts are skewed towards small values but regularly exceed what
+// the sender can have, so rejections are exercised too.
+func (cfg simulationConfig) plan() []simulationTransfer {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	out := make([]simulationTransfer, cfg.Transfers)
+	for i := range out {
+		from := rng.Intn(cfg.Accounts)
+		n := 1
+		if rng.Intn(10) == 0 {
+			n = 2 + rng.Intn(7)
+		}
+		out[i] = simulationTransfer{From: from, Lines: make([]simulationLine, n)}
+		for j := range out[i].Lines {
+			to := rng.Intn(cfg.Accounts - 1)
+			if to >= from {
+				to++
+			}
+			amount := 1 + rng.Int63n(cfg.Balance/10)
+			if rng.Intn(10) == 0 {
+				amount = 1 + rng.Int63n(cfg.Balance*3)
+			}
+			out[i].Lines[j] = simulationLine{To: to, Amount: amount}
+		}
+	}
+	return out
+}
+
+func TestSimulation_MoneyConservation(t *testing.T) {
+	cfg := newSimulationConfig(t)
+	t.Logf("simulation seed %d; rerun with TEST_SIM_SEED=%d", cfg.Seed, cfg.Seed)
+
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryTransferRepo()
+		ids := make([]int64, cfg.Accounts)
+		for i := range ids {
+			ids[i] = int64(i + 1)
+			require.NoError(t, store.SeedUser(model.User{ID: ids[i], Name: fmt.Sprintf("sim-%d", i), Balance: cfg.Balance}))
+		}
+		runSimulation(t, cfg, store, ids)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		db := setupTestDB(t)
+		balances := make([]int64, cfg.Accounts)
+		for i := range balances {
+			balances[i] = cfg.Balance
+		}
+		r := NewPostgresTransferRepo(db, config.LoadConfig())
+		runSimulation(t, cfg, r, createTestUsers(t, db, balances...))
+	})
+}
+
+// runSimulation runs cfg's plan across cfg.Workers goroutines against
+// accounts that each start with cfg.Balance, while hot accounts are swept in
+// the background, then checks that money was conserved, that no balance went
+// negative and that the ledger accounts for every balance.
+func runSimulation(t *testing.T, cfg simulationConfig, r simulationRepo, ids []int64) {
+	ctx := context.Background()
+	fail := func(format string, args ...interface{}) {
+		t.Helper()
+		t.Fatalf("%s (seed %d)", fmt.Sprintf(format, args...), cfg.Seed)
+	}
+	for _, id := range ids[:cfg.HotAccounts] {
+		require.NoError(t, r.SetHotBuckets(ctx, id, 4, model.AuditEvent{Action: model.AuditSetHotBuckets}))
+	}
+
+	plan := cfg.plan()
+	jobs := make(chan simulationTransfer)
+	var (
+		wg        sync.WaitGroup
+		committed sync.Map // transaction ID -> model.Transaction
+		rejected  atomic.Int64
+		errs      = make(chan error, cfg.Workers+2)
+	)
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				txs, err := runSimulationTransfer(ctx, r, ids, job)
+				switch {
+				case errors.Is(err, service.ErrInsufficientFunds):
+					rejected.Add(int64(len(job.Lines)))
+				case err != nil:
+					errs <- fmt.Errorf("transfer from %d: %w", ids[job.From], err)
+					return
+				default:
+					for _, tx := range txs {
+						committed.Store(tx.ID, tx)
+					}
+				}
+			}
+		}()
+	}
+
+	// An observer reads balances while transfers are in flight.
+	stop := make(chan struct{})
+	observed := make(chan struct{})
+	go func() {
+		defer close(observed)
+		rng := rand.New(rand.NewSource(cfg.Seed + 1))
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			id := ids[rng.Intn(len(ids))]
+			balance, err := r.GetBalance(ctx, id)
+			if err == nil && balance < 0 {
+				err = fmt.Errorf("user %d observed with balance %d", id, balance)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	// A sweeper folds hot-account buckets back into balances.
+	swept := make(chan struct{})
+	go func() {
+		defer close(swept)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+			}
+			if _, err := r.SweepHotAccounts(ctx); err != nil {
+				errs <- fmt.Errorf("sweep: %w", err)
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	for _, job := range plan {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+	close(stop)
+	<-observed
+	<-swept
+	close(errs)
+	for err := range errs {
+		fail("%v", err)
+	}
+
+	var txs []model.Transaction
+	committed.Range(func(_, v interface{}) bool {
+		txs = append(txs, v.(model.Transaction))
+		return true
+	})
+	planned := 0
+	for _, job := range plan {
+		planned += len(job.Lines)
+	}
+	t.Logf("%d transfer lines committed, %d rejected in %s", len(txs), rejected.Load(), time.Since(start).Round(time.Millisecond))
+	if len(txs)+int(rejected.Load()) != planned {
+		fail("%d committed + %d rejected != %d planned", len(txs), rejected.Load(), planned)
+	}
+
+	// Balance deltas implied by the transfers the repository acknowledged.
+	index := make(map[int64]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	want := make([]int64, len(ids))
+	for i := range want {
+		want[i] = cfg.Balance
+	}
+	for _, tx := range txs {
+		want[index[tx.From]] -= tx.Amount
+		want[index[tx.To]] += tx.Amount
+	}
+
+	var total int64
+	ledgerIDs := map[uint]bool{}
+	for i, id := range ids {
+		balance, err := r.GetBalance(ctx, id)
+		require.NoError(t, err)
+		if balance < 0 {
+			fail("user %d ended with balance %d", id, balance)
+		}
+		if balance != want[i] {
+			fail("user %d has balance %d, acknowledged transfers give %d", id, balance, want[i])
+		}
+		total += balance
+
+		ledger, err := listAllUserTransactions(ctx, r, id)
+		require.NoError(t, err)
+		delta := int64(0)
+		for _, tx := range ledger {
+			if tx.Amount <= 0 {
+				fail("ledger row %d has amount %d", tx.ID, tx.Amount)
+			}
+			if tx.From == id {
+				delta -= tx.Amount
+			} else {
+				delta += tx.Amount
+			}
+			ledgerIDs[tx.ID] = true
+		}
+		if cfg.Balance+delta != balance {
+			fail("user %d: ledger moves %d but balance changed by %d", id, delta, balance-cfg.Balance)
+		}
+	}
+
+	if want := cfg.Balance * int64(len(ids)); total != want {
+		fail("total money %d, want %d", total, want)
+	}
+	if len(ledgerIDs) != len(txs) {
+		fail("%d ledger rows for %d acknowledged transfers", len(ledgerIDs), len(txs))
+	}
+	for _, tx := range txs {
+		if !ledgerIDs[tx.ID] {
+			fail("acknowledged transaction %d missing from the ledger", tx.ID)
+		}
+	}
+}
+
+// runSimulationTransfer sends single-line jobs with InsertTransaction and the
+// rest as one batch.
+func runSimulationTransfer(ctx context.Context, r simulationRepo, ids []int64, job simulationTransfer) ([]model.Transaction, error) {
+	if len(job.Lines) == 1 {
+		tx, err := r.InsertTransaction(ctx, ids[job.From], ids[job.Lines[0].To], job.Lines[0].Amount, model.AuditEvent{})
+		if err != nil {
+			return nil, err
+		}
+		return []model.Transaction{tx}, nil
+	}
+	lines := make([]model.TransferLine, len(job.Lines))
+	for i, l := range job.Lines {
+		lines[i] = model.TransferLine{To: ids[l.To], Amount: l.Amount}
+	}
+	return r.InsertTransactions(ctx, ids[job.From], lines, model.AuditEvent{})
+}
+
+func listAllUserTransactions(ctx context.Context, r simulationRepo, userID int64) ([]model.Transaction, error) {
+	var all []model.Transaction
+	var before uint
+	for {
+		page, err := r.ListUserTransactions(ctx, userID, before, 500)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < 500 {
+			return all, nil
+		}
+		before = page[len(page)-1].ID
+	}
+}
@@ -2,10 +2,12 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"fmt"
 	"project/config"
 	"project/internal/model"
+	"project/internal/service"
+	"slices"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,24 +15,33 @@ import (
 )
 
 type GormTransferRepo struct {
-	db *gorm.DB
+	db    *gorm.DB
+	retry retryPolicy
 }
 
+// NewPostgresDB connects to the database and refuses to start against a
+// schema older than this build. It never changes the schema; run `migrate up`.
 func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{})
+	db, err := OpenPostgres(cfg)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := db.AutoMigrate(&model.User{}, &model.Transaction{}); err != nil {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckSchemaVersion(context.Background(), sqlDB); err != nil {
 		return nil, err
 	}
-
 	return db, nil
 }
 
-func NewPostgresTransferRepo(db *gorm.DB) *GormTransferRepo {
-	return &GormTransferRepo{db: db}
+func OpenPostgres(cfg *config.Config) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{})
+}
+
+func NewPostgresTransferRepo(db *gorm.DB, cfg *config.Config) *GormTransferRepo {
+	return &GormTransferRepo{db: db, retry: newRetryPolicy(cfg.Database)}
 }
 
 func (r *GormTransferRepo) ListTransactions(ctx context.Context, from int64) ([]model.Transaction, error) {
@@ -44,82 +55,261 @@ func (r *GormTransferRepo) ListTransactions(ctx context.Context, from int64) ([]
 	return txs, nil
 }
 
-func (r *GormTransferRepo) InsertTransaction(ctx context.Context, from, to int64, amount int64) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var firstID, secondID int64
-		if from < to {
-			firstID, secondID = from, to
-		} else {
-			firstID, secondID = to, from
-		}
+// ScanTransactions returns up to limit transactions with ID greater than
+// afterID, in ID order, so callers can page through the whole table.
+func (r *GormTransferRepo) ScanTransactions(ctx context.Context, afterID uint, scan model.TransactionScan, limit int) ([]model.Transaction, error) {
+	qs := model.NewTransactionQuerySet(r.db.WithContext(ctx)).IDGt(afterID)
+	if scan.ToID > 0 {
+		qs = qs.IDLte(scan.ToID)
+	}
+	if !scan.Since.IsZero() {
+		qs = qs.CreatedAtGte(scan.Since)
+	}
+	if !scan.Until.IsZero() {
+		qs = qs.CreatedAtLt(scan.Until)
+	}
 
-		var user1, user2 model.User
+	var txs []model.Transaction
+	if err := qs.OrderAscByID().Limit(limit).All(&txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
 
-		if err := model.NewUserQuerySet(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
-			IDEq(firstID).
-			One(&user1); err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return fmt.Errorf("user %d not found", firstID)
-			}
+// InsertTransaction moves amount and records event in the same transaction,
+// with the transaction ID added to its details.
+func (r *GormTransferRepo) InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error) {
+	var created model.Transaction
+	err := r.transaction(ctx, "InsertTransaction", func(tx *gorm.DB) error {
+		var err error
+		if created, err = moveOne(tx, from, to, amount, false); err != nil {
+			return err
+		}
+		e := event
+		if e.Details, err = withDetail(e.Details, "transaction_id", created.ID); err != nil {
 			return err
 		}
+		return queueAuditEvent(tx, e)
+	})
+	return created, err
+}
 
-		if err := model.NewUserQuerySet(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
-			IDEq(secondID).
-			One(&user2); err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return fmt.Errorf("user %d not found", secondID)
-			}
+// InsertTransactions moves every line from one sender in a single
+// transaction: either all lines commit or none do. event is recorded in the
+// same transaction, with the batch ID added to its details.
+func (r *GormTransferRepo) InsertTransactions(ctx context.Context, from int64, lines []model.TransferLine, event model.AuditEvent) ([]model.Transaction, error) {
+	var created []model.Transaction
+	err := r.transaction(ctx, "InsertTransactions", func(tx *gorm.DB) error {
+		var err error
+		if created, err = moveMoney(tx, transfer{from: from, lines: lines, batch: true}); err != nil {
 			return err
 		}
+		e := event
+		if e.Details, err = withDetail(e.Details, "batch_id", model.BatchID(created)); err != nil {
+			return err
+		}
+		return queueAuditEvent(tx, e)
+	})
+	return created, err
+}
+
+// transfer is a debit of one account to one or more recipients.
+type transfer struct {
+	from  int64
+	lines []model.TransferLine
+	// adjustment marks admin adjustments, which may touch frozen and
+	// receive-only accounts and let the system account go negative.
+	adjustment bool
+	// batch adds the failing line's index to errors about a recipient.
+	batch bool
+}
+
+func singleTransfer(from, to, amount int64, adjustment bool) transfer {
+	return transfer{from: from, lines: []model.TransferLine{{To: to, Amount: amount}}, adjustment: adjustment}
+}
+
+// moveOne is moveMoney for a single recipient.
+func moveOne(tx *gorm.DB, from, to, amount int64, adjustment bool) (model.Transaction, error) {
+	txs, err := moveMoney(tx, singleTransfer(from, to, amount, adjustment))
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return txs[0], nil
+}
+
+func (t transfer) total() int64 {
+	var total int64
+	for _, l := range t.lines {
+		total += l.Amount
+	}
+	return total
+}
+
+// lineError attributes err to the first line paying userID.
+func (t transfer) lineError(userID int64, err error) error {
+	if !t.batch {
+		return err
+	}
+	for i, l := range t.lines {
+		if l.To == userID {
+			return service.LineError(i, err)
+		}
+	}
+	return err
+}
+
+// accountIDs returns the sender and recipients once each, in ID order, the
+// order every transfer locks them in so that two transfers never wait on
+// each other's locks.
+func (t transfer) accountIDs() []int64 {
+	ids := []int64{t.from}
+	for _, l := range t.lines {
+		ids = append(ids, l.To)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
 
-		var fromUser, toUser *model.User
-		if firstID == from {
-			fromUser, toUser = &user1, &user2
+// moveMoney locks the accounts in ID order, checks their statuses, moves the
+// money and records one transaction per line, in line order, with its events
+// in the outbox. Credits to a hot account land in one of its buckets; see
+// hot.go.
+func moveMoney(tx *gorm.DB, t transfer) ([]model.Transaction, error) {
+	ids := t.accountIDs()
+	hot, err := recipientBuckets(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int64]*model.User, len(ids))
+	for _, id := range ids {
+		// The lock taken decides where credits go: under KEY SHARE the
+		// users row must not be written.
+		strength := "UPDATE"
+		if id != t.from && hot[id] > 0 {
+			strength = "KEY SHARE"
 		} else {
-			fromUser, toUser = &user2, &user1
+			delete(hot, id)
+		}
+		var user model.User
+		if err := lockUser(tx, id, strength, &user); err != nil {
+			return nil, t.lineError(id, err)
+		}
+		if strength == "KEY SHARE" {
+			// SetHotBuckets holds the row FOR UPDATE, so the locked row's
+			// count is current. If hot mode was just turned off, the credit
+			// must go to the row, which needs the stronger lock.
+			hot[id] = user.HotBuckets
+			if user.HotBuckets == 0 {
+				delete(hot, id)
+				if err := lockUser(tx, id, "UPDATE", &user); err != nil {
+					return nil, t.lineError(id, err)
+				}
+			}
 		}
+		users[id] = &user
+	}
 
-		if fromUser.Balance < amount {
-			return fmt.Errorf("insufficient balance")
+	fromUser := users[t.from]
+	if err := checkSender(fromUser, t.adjustment); err != nil {
+		return nil, err
+	}
+	for _, l := range t.lines {
+		if err := checkRecipient(users[l.To], t.adjustment); err != nil {
+			return nil, t.lineError(l.To, err)
 		}
+	}
 
-		if err := model.NewUserQuerySet(tx).
-			IDEq(fromUser.ID).
-			GetUpdater().
-			SetBalance(fromUser.Balance - amount).
-			Update(); err != nil {
-			return err
+	total := t.total()
+	overdraft := t.adjustment && fromUser.ID == model.SystemAccountID
+	if !overdraft && fromUser.Balance < total {
+		// Unswept credits count towards what a hot account can spend.
+		if err := sweepInto(tx, fromUser); err != nil {
+			return nil, err
+		}
+		if fromUser.Balance < total {
+			return nil, service.ErrInsufficientFunds
 		}
+	}
 
+	fromUser.Balance -= total
+	changed := map[int64]bool{t.from: true}
+	for _, l := range t.lines {
+		if buckets, ok := hot[l.To]; ok {
+			if err := creditBucket(tx, l.To, buckets, l.Amount); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		users[l.To].Balance += l.Amount
+		changed[l.To] = true
+	}
+	for _, id := range ids {
+		if !changed[id] {
+			continue
+		}
 		if err := model.NewUserQuerySet(tx).
-			IDEq(toUser.ID).
+			IDEq(id).
 			GetUpdater().
-			SetBalance(toUser.Balance + amount).
+			SetBalance(users[id].Balance).
 			Update(); err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		newTx := model.Transaction{
-			From:   from,
-			To:     to,
-			Amount: amount,
-		}
-		if err := tx.Create(&newTx).Error; err != nil {
-			return err
+	txs := make([]model.Transaction, len(t.lines))
+	for i, l := range t.lines {
+		txs[i] = model.Transaction{From: t.from, To: l.To, Amount: l.Amount}
+	}
+	if err := tx.Create(&txs).Error; err != nil {
+		return nil, err
+	}
+	if err := writeOutbox(tx, t, txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func lockUser(tx *gorm.DB, userID int64, strength string, user *model.User) error {
+	err := model.NewUserQuerySet(tx.Clauses(clause.Locking{Strength: strength})).IDEq(userID).One(user)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service.UserNotFound(userID)
+	}
+	return err
+}
+
+// checkSender and checkRecipient run with the rows locked, so a concurrent
+// status change cannot slip between the check and the balance update.
+func checkSender(from *model.User, adjustment bool) error {
+	if adjustment {
+		if from.Status == model.StatusClosed {
+			return service.ErrSenderClosed
 		}
+		return nil
+	}
+	return service.SenderStatusError(from.Status)
+}
 
+func checkRecipient(to *model.User, adjustment bool) error {
+	if adjustment {
+		if to.Status == model.StatusClosed {
+			return service.ErrRecipientClosed
+		}
 		return nil
-	})
+	}
+	return service.RecipientStatusError(to.Status)
 }
 
+// GetBalance reports the aggregate balance, including unswept credits.
 func (r *GormTransferRepo) GetBalance(ctx context.Context, userID int64) (int64, error) {
-	var user model.User
-	err := model.NewUserQuerySet(r.db).IDEq(userID).One(&user)
+	balance, err := aggregateBalance(r.db.WithContext(ctx), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, service.UserNotFound(userID)
+	}
 	if err != nil {
 		return 0, err
 	}
-	return user.Balance, nil
+	return balance, nil
 }
 
 func (r *GormTransferRepo) GetPassword(ctx context.Context, userID int64) (string, error) {
@@ -130,3 +320,24 @@ func (r *GormTransferRepo) GetPassword(ctx context.Context, userID int64) (strin
 	}
 	return user.Password, nil
 }
+
+func (r *GormTransferRepo) GetAccountStatus(ctx context.Context, userID int64) (string, error) {
+	var user model.User
+	err := model.NewUserQuerySet(r.db.WithContext(ctx)).Select(model.UserDBSchema.Status).IDEq(userID).One(&user)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", service.UserNotFound(userID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Status, nil
+}
+
+func (r *GormTransferRepo) GetRoles(ctx context.Context, userID int64) ([]string, error) {
+	var user model.User
+	err := model.NewUserQuerySet(r.db.WithContext(ctx)).IDEq(userID).One(&user)
+	if err != nil {
+		return nil, err
+	}
+	return user.RoleList(), nil
+}
@@ -3,38 +3,83 @@ package repo
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"testing"
 	"time"
 
+	"project/config"
+	"project/internal/model"
+	"project/internal/service"
+
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
+// setupTestDB connects to TEST_DATABASE_URL, or the docker-compose database,
+// and migrates it. Tests are skipped when neither is reachable.
 func setupTestDB(t *testing.T) *gorm.DB {
-	dsn := "host=localhost user=demo_user password=demo_pass dbname=demo_db sslmode=disable connect_timeout=10"
+	db := openTestDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	migrator, err := NewMigrator(sqlDB)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err, "failed to migrate test database")
+
+	return db
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	require.NoError(t, err, "failed to open GORM v2 DB")
+// openTestDB connects without migrating.
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		dsn = "host=localhost user=demo_user password=demo_pass dbname=demo_db sslmode=disable connect_timeout=10"
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Skipf("Postgres not available (%v); set TEST_DATABASE_URL to run", err)
+	}
 
 	sqlDB, err := db.DB()
 	require.NoError(t, err, "failed to get sql.DB from GORM")
+	t.Cleanup(func() { sqlDB.Close() })
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	require.NoError(t, sqlDB.PingContext(ctx), "failed to connect to test database")
-
+	if err := sqlDB.PingContext(ctx); err != nil {
+		t.Skipf("Postgres not available (%v); set TEST_DATABASE_URL to run", err)
+	}
 	return db
 }
+
+// createTestUsers inserts an active user per balance, so tests do not depend
+// on (or disturb) seeded data.
+func createTestUsers(t *testing.T, db *gorm.DB, balances ...int64) []int64 {
+	ids := make([]int64, len(balances))
+	for i, balance := range balances {
+		u := model.User{Name: fmt.Sprintf("%s-%d", t.Name(), i), Password: "!", Balance: balance, Roles: model.RoleUser, Status: model.StatusActive}
+		require.NoError(t, db.Create(&u).Error)
+		ids[i] = u.ID
+	}
+	return ids
+}
+
 func TestInsertTransaction_Success(t *testing.T) {
 	db := setupTestDB(t)
 	repo := &GormTransferRepo{db: db}
 
 	ctx := context.Background()
-	from := int64(1)
-	to := int64(2)
+	ids := createTestUsers(t, db, 100, 100)
+	from := ids[0]
+	to := ids[1]
 	amount := int64(2)
 
 	fromBalanceBefore, err := repo.GetBalance(ctx, from)
@@ -43,7 +88,7 @@ func TestInsertTransaction_Success(t *testing.T) {
 	require.NoError(t, err)
 	fmt.Printf("[Before] From: %d, To: %d\n", fromBalanceBefore, toBalanceBefore)
 
-	err = repo.InsertTransaction(ctx, from, to, amount)
+	_, err = repo.InsertTransaction(ctx, from, to, amount, model.AuditEvent{})
 	require.NoError(t, err)
 
 	fromBalanceAfter, err := repo.GetBalance(ctx, from)
@@ -61,8 +106,9 @@ func TestInsertTransaction_InsufficientBalance(t *testing.T) {
 	repo := &GormTransferRepo{db: db}
 
 	ctx := context.Background()
-	from := int64(1)
-	to := int64(2)
+	ids := createTestUsers(t, db, 100, 100)
+	from := ids[0]
+	to := ids[1]
 	amount := int64(2000000)
 
 	fromBalanceBefore, err := repo.GetBalance(ctx, from)
@@ -71,10 +117,10 @@ func TestInsertTransaction_InsufficientBalance(t *testing.T) {
 	require.NoError(t, err)
 
 	var countBefore int64
-	require.NoError(t, db.Table("transactions").Count(&countBefore).Error)
+	require.NoError(t, db.Table("transactions").Where("from_user = ?", from).Count(&countBefore).Error)
 	fmt.Printf("[Before] From: %d, To: %d\n", fromBalanceBefore, toBalanceBefore)
 
-	err = repo.InsertTransaction(ctx, from, to, amount)
+	_, err = repo.InsertTransaction(ctx, from, to, amount, model.AuditEvent{})
 	require.Error(t, err)
 
 	fromBalanceAfter, err := repo.GetBalance(ctx, from)
@@ -87,7 +133,7 @@ func TestInsertTransaction_InsufficientBalance(t *testing.T) {
 	fmt.Printf("[After] From: %d, To: %d\n", fromBalanceAfter, toBalanceAfter)
 
 	var countAfter int64
-	require.NoError(t, db.Table("transactions").Count(&countAfter).Error)
+	require.NoError(t, db.Table("transactions").Where("from_user = ?", from).Count(&countAfter).Error)
 	require.Equal(t, countBefore, countAfter)
 }
 
@@ -95,8 +141,9 @@ func TestInsertTransaction_Concurrent(t *testing.T) {
 	db := setupTestDB(t)
 	repo := &GormTransferRepo{db: db}
 
-	from := int64(2)
-	to := int64(1)
+	ids := createTestUsers(t, db, 100, 100)
+	from := ids[0]
+	to := ids[1]
 	n := 50
 	amount := int64(1)
 
@@ -120,7 +167,7 @@ func TestInsertTransaction_Concurrent(t *testing.T) {
 			jobCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 			defer cancel()
 
-			if err := repo.InsertTransaction(jobCtx, from, to, amount); err != nil {
+			if _, err := repo.InsertTransaction(jobCtx, from, to, amount, model.AuditEvent{}); err != nil {
 				errs <- fmt.Errorf("job %d failed: %w", job, err)
 			}
 		}(i)
@@ -157,10 +204,12 @@ func TestInsertTransaction_InvalidUsers(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := repo.InsertTransaction(ctx, -1, 1, 100)
+	id := createTestUsers(t, db, 1000)[0]
+
+	_, err := repo.InsertTransaction(ctx, -1, id, 100, model.AuditEvent{})
 	require.Error(t, err)
 
-	err = repo.InsertTransaction(ctx, 1, -1, 100)
+	_, err = repo.InsertTransaction(ctx, id, -1, 100, model.AuditEvent{})
 	require.Error(t, err)
 }
 
@@ -169,8 +218,9 @@ func TestInsertTransaction_ConcurrentDeadlock(t *testing.T) {
 	db := setupTestDB(t)
 	repo := &GormTransferRepo{db: db}
 
-	userA := int64(1)
-	userB := int64(2)
+	ids := createTestUsers(t, db, 100, 100)
+	userA := ids[0]
+	userB := ids[1]
 	amount := int64(1)
 	amount2 := int64(10)
 
@@ -184,7 +234,7 @@ func TestInsertTransaction_ConcurrentDeadlock(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := repo.InsertTransaction(ctx, userA, userB, amount); err != nil {
+		if _, err := repo.InsertTransaction(ctx, userA, userB, amount, model.AuditEvent{}); err != nil {
 			errs <- fmt.Errorf("A→B failed: %w", err)
 		}
 	}()
@@ -192,7 +242,7 @@ func TestInsertTransaction_ConcurrentDeadlock(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := repo.InsertTransaction(ctx, userB, userA, amount2); err != nil {
+		if _, err := repo.InsertTransaction(ctx, userB, userA, amount2, model.AuditEvent{}); err != nil {
 			errs <- fmt.Errorf("B→A failed: %w", err)
 		}
 	}()
@@ -215,3 +265,93 @@ func TestInsertTransaction_ConcurrentDeadlock(t *testing.T) {
 	require.Equal(t, 0, errorCount, "phát hiện lỗi trong giao dịch đồng thời")
 
 }
+
+func TestInsertTransaction_AccountStatus(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &GormTransferRepo{db: db}
+	ctx := context.Background()
+
+	ids := createTestUsers(t, db, 100, 100)
+	a, b := ids[0], ids[1]
+
+	setStatus := func(id int64, status string) {
+		require.NoError(t, db.Model(&model.User{}).Where("id = ?", id).Update("status", status).Error)
+	}
+
+	setStatus(a, model.StatusFrozen)
+	_, err := repo.InsertTransaction(ctx, a, b, 1, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrSenderFrozen)
+	_, err = repo.InsertTransaction(ctx, b, a, 1, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrRecipientFrozen)
+
+	setStatus(a, model.StatusReceiveOnly)
+	_, err = repo.InsertTransaction(ctx, a, b, 1, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrSenderReceiveOnly)
+	_, err = repo.InsertTransaction(ctx, b, a, 1, model.AuditEvent{})
+	require.NoError(t, err)
+}
+
+type batchRepo interface {
+	InsertTransactions(ctx context.Context, from int64, lines []model.TransferLine, event model.AuditEvent) ([]model.Transaction, error)
+	GetBalance(ctx context.Context, userID int64) (int64, error)
+	SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error
+	SetAccountStatus(ctx context.Context, userID int64, status, reason string, at time.Time, event model.AuditEvent) (model.User, error)
+}
+
+func TestInsertTransactions(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		r := NewMemoryTransferRepo()
+		for i, balance := range []int64{100, 10, 10, 10} {
+			require.NoError(t, r.SeedUser(model.User{ID: int64(i + 1), Name: "batch", Balance: balance}))
+		}
+		testInsertTransactions(t, r, []int64{1, 2, 3, 4})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		db := setupTestDB(t)
+		testInsertTransactions(t, NewPostgresTransferRepo(db, config.LoadConfig()), createTestUsers(t, db, 100, 10, 10, 10))
+	})
+}
+
+func testInsertTransactions(t *testing.T, r batchRepo, ids []int64) {
+	ctx := context.Background()
+	payer, a, b, c := ids[0], ids[1], ids[2], ids[3]
+	balances := func() []int64 {
+		t.Helper()
+		out := make([]int64, len(ids))
+		for i, id := range ids {
+			var err error
+			out[i], err = r.GetBalance(ctx, id)
+			require.NoError(t, err)
+		}
+		return out
+	}
+	require.NoError(t, r.SetHotBuckets(ctx, c, 2, model.AuditEvent{Action: model.AuditSetHotBuckets}))
+	_, err := r.SetAccountStatus(ctx, b, model.StatusFrozen, "", time.Now(), model.AuditEvent{Action: model.AuditSetAccountStatus})
+	require.NoError(t, err)
+
+	// Any failing line rolls back the whole batch and names the line.
+	_, err = r.InsertTransactions(ctx, payer, []model.TransferLine{{To: a, Amount: 5}, {To: c, Amount: 5}, {To: b, Amount: 5}}, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrRecipientFrozen)
+	e, _ := service.AsError(err)
+	require.Equal(t, "2", e.Metadata["line"])
+	_, err = r.InsertTransactions(ctx, payer, []model.TransferLine{{To: a, Amount: 5}, {To: 1 << 40, Amount: 5}}, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+	e, _ = service.AsError(err)
+	require.Equal(t, "1", e.Metadata["line"])
+	_, err = r.InsertTransactions(ctx, payer, []model.TransferLine{{To: a, Amount: 60}, {To: c, Amount: 41}}, model.AuditEvent{})
+	require.ErrorIs(t, err, service.ErrInsufficientFunds)
+	require.Equal(t, []int64{100, 10, 10, 10}, balances())
+
+	txs, err := r.InsertTransactions(ctx, payer, []model.TransferLine{{To: c, Amount: 20}, {To: a, Amount: 30}, {To: c, Amount: 15}}, model.AuditEvent{})
+	require.NoError(t, err)
+	require.Len(t, txs, 3)
+	for i, want := range []model.TransferLine{{To: c, Amount: 20}, {To: a, Amount: 30}, {To: c, Amount: 15}} {
+		require.Equal(t, payer, txs[i].From)
+		require.Equal(t, want.To, txs[i].To)
+		require.Equal(t, want.Amount, txs[i].Amount)
+		if i > 0 {
+			require.Greater(t, txs[i].ID, txs[i-1].ID)
+		}
+	}
+	require.Equal(t, []int64{35, 40, 10, 45}, balances())
+}
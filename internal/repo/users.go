@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+
+	"project/internal/model"
+	"project/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// CreateUser inserts user, funds an opening balance from the system account
+// and records event, all in one transaction. The user's ID and balance are
+// filled in on success.
+func (r *GormTransferRepo) CreateUser(ctx context.Context, user *model.User, balance int64, event model.AuditEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user.Balance = 0
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if balance > 0 {
+			created, err := moveOne(tx, model.SystemAccountID, user.ID, balance, true)
+			if err != nil {
+				return err
+			}
+			if event.Details, err = withDetail(event.Details, "transaction_id", created.ID); err != nil {
+				return err
+			}
+			user.Balance = balance
+		}
+		event.TargetUserID = user.ID
+		event.After = stateJSON(map[string]interface{}{"name": user.Name, "roles": user.Roles, "balance": user.Balance})
+		return appendAuditEvent(tx, &event)
+	})
+}
+
+// SetPassword replaces the stored password hash and records event in the
+// same transaction.
+func (r *GormTransferRepo) SetPassword(ctx context.Context, userID int64, hash string, event model.AuditEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		n, err := model.NewUserQuerySet(tx).IDEq(userID).GetUpdater().SetPassword(hash).UpdateNum()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return service.UserNotFound(userID)
+		}
+		return appendAuditEvent(tx, &event)
+	})
+}
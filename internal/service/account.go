@@ -0,0 +1,55 @@
+package service
+
+import "project/internal/model"
+
+// SenderStatusError reports whether an account in status may send money.
+func SenderStatusError(status string) error {
+	switch status {
+	case model.StatusActive:
+		return nil
+	case model.StatusFrozen:
+		return ErrSenderFrozen
+	case model.StatusClosed:
+		return ErrSenderClosed
+	case model.StatusReceiveOnly:
+		return ErrSenderReceiveOnly
+	}
+	return ErrSenderFrozen
+}
+
+// RecipientStatusError reports whether an account in status may receive
+// money.
+func RecipientStatusError(status string) error {
+	switch status {
+	case model.StatusActive, model.StatusReceiveOnly:
+		return nil
+	case model.StatusClosed:
+		return ErrRecipientClosed
+	}
+	return ErrRecipientFrozen
+}
+
+// ValidateStatusTransition checks that user may move to status. Closed is
+// final, and only an empty account can be closed. Repositories call it with
+// the user row locked.
+func ValidateStatusTransition(user model.User, status string) error {
+	if !isAccountStatus(status) {
+		return ErrInvalidStatus
+	}
+	if user.ID == model.SystemAccountID || user.Status == model.StatusClosed {
+		return ErrInvalidStatusTransition
+	}
+	if status == model.StatusClosed && user.Balance != 0 {
+		return ErrBalanceNotZero
+	}
+	return nil
+}
+
+func isAccountStatus(status string) bool {
+	for _, s := range model.AccountStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"project/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusErrors(t *testing.T) {
+	require.NoError(t, SenderStatusError(model.StatusActive))
+	require.ErrorIs(t, SenderStatusError(model.StatusFrozen), ErrSenderFrozen)
+	require.ErrorIs(t, SenderStatusError(model.StatusClosed), ErrSenderClosed)
+	require.ErrorIs(t, SenderStatusError(model.StatusReceiveOnly), ErrSenderReceiveOnly)
+
+	require.NoError(t, RecipientStatusError(model.StatusActive))
+	require.NoError(t, RecipientStatusError(model.StatusReceiveOnly))
+	require.ErrorIs(t, RecipientStatusError(model.StatusFrozen), ErrRecipientFrozen)
+	require.ErrorIs(t, RecipientStatusError(model.StatusClosed), ErrRecipientClosed)
+
+	// Unknown statuses fail closed.
+	require.Error(t, SenderStatusError(""))
+	require.Error(t, RecipientStatusError(""))
+}
+
+func TestValidateStatusTransition(t *testing.T) {
+	active := model.User{ID: 1, Status: model.StatusActive}
+	require.NoError(t, ValidateStatusTransition(active, model.StatusReceiveOnly))
+	require.NoError(t, ValidateStatusTransition(active, model.StatusClosed))
+	require.ErrorIs(t, ValidateStatusTransition(active, "deleted"), ErrInvalidStatus)
+
+	funded := model.User{ID: 1, Status: model.StatusFrozen, Balance: 1}
+	require.ErrorIs(t, ValidateStatusTransition(funded, model.StatusClosed), ErrBalanceNotZero)
+
+	closed := model.User{ID: 1, Status: model.StatusClosed}
+	require.ErrorIs(t, ValidateStatusTransition(closed, model.StatusActive), ErrInvalidStatusTransition)
+
+	system := model.User{ID: model.SystemAccountID, Status: model.StatusActive}
+	require.ErrorIs(t, ValidateStatusTransition(system, model.StatusFrozen), ErrInvalidStatusTransition)
+}
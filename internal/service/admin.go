@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"project/internal/model"
+	"project/internal/utils"
+)
+
+const (
+	defaultAdminPageSize = 50
+	maxAdminPageSize     = 200
+)
+
+type AdminRepo interface {
+	SearchUsers(ctx context.Context, query string, afterID int64, limit int) ([]model.User, error)
+	GetUser(ctx context.Context, userID int64) (model.User, error)
+	ListUserTransactions(ctx context.Context, userID int64, beforeID uint, limit int) ([]model.Transaction, error)
+	AdjustBalance(ctx context.Context, userID, amount int64, event model.AuditEvent) (model.Transaction, int64, error)
+	SetAccountStatus(ctx context.Context, userID int64, status, reason string, at time.Time, event model.AuditEvent) (model.User, error)
+	RecordAuditEvent(ctx context.Context, event model.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter model.AuditFilter, beforeID int64, limit int) ([]model.AuditEvent, error)
+}
+
+// AdminService implements support operations. Every call, including reads,
+// is recorded as an audit event; a call whose event cannot be written fails.
+type AdminService struct {
+	repo  AdminRepo
+	redis RedisClient
+	now   func() time.Time
+}
+
+func NewAdminService(repo AdminRepo, redis RedisClient) *AdminService {
+	return &AdminService{repo: repo, redis: redis, now: time.Now}
+}
+
+func (s *AdminService) ListUsers(ctx context.Context, in model.ListUsersInput) (*model.ListUsersOutput, error) {
+	limit, err := pageSize(in.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	afterID, err := parsePageToken(in.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(in.Query)
+
+	users, err := s.repo.SearchUsers(ctx, query, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, in.ActorID, model.AuditListUsers, 0, "", map[string]interface{}{"query": query}); err != nil {
+		return nil, err
+	}
+
+	out := &model.ListUsersOutput{Users: users}
+	if len(users) > limit {
+		out.Users = users[:limit]
+		out.NextPageToken = strconv.FormatInt(out.Users[limit-1].ID, 10)
+	}
+	return out, nil
+}
+
+func (s *AdminService) GetUser(ctx context.Context, in model.GetUserInput) (*model.User, error) {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	user, err := s.repo.GetUser(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, in.ActorID, model.AuditGetUser, in.UserID, "", nil); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *AdminService) ListUserTransactions(ctx context.Context, in model.ListUserTransactionsInput) (*model.ListUserTransactionsOutput, error) {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	limit, err := pageSize(in.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := parsePageToken(in.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetUser(ctx, in.UserID); err != nil {
+		return nil, err
+	}
+
+	txs, err := s.repo.ListUserTransactions(ctx, in.UserID, uint(beforeID), limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, in.ActorID, model.AuditListUserTransactions, in.UserID, "", nil); err != nil {
+		return nil, err
+	}
+
+	out := &model.ListUserTransactionsOutput{Transactions: txs}
+	if len(txs) > limit {
+		out.Transactions = txs[:limit]
+		out.NextPageToken = strconv.FormatUint(uint64(out.Transactions[limit-1].ID), 10)
+	}
+	return out, nil
+}
+
+func (s *AdminService) AdjustBalance(ctx context.Context, in model.AdjustBalanceInput) (*model.AdjustBalanceOutput, error) {
+	return adjustBalance(ctx, s.repo, in)
+}
+
+type balanceAdjuster interface {
+	AdjustBalance(ctx context.Context, userID, amount int64, event model.AuditEvent) (model.Transaction, int64, error)
+}
+
+// adjustBalance posts a credit or debit against the system account. It is
+// shared by the admin API and the users CLI.
+func adjustBalance(ctx context.Context, repo balanceAdjuster, in model.AdjustBalanceInput) (*model.AdjustBalanceOutput, error) {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	if err := validateAdjustment(in.Amount); err != nil {
+		return nil, InvalidArgument("INVALID_AMOUNT", err)
+	}
+	reason, err := requireReason(in.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := newAuditEvent(ctx, in.ActorID, model.AuditAdjustBalance, in.UserID, reason, map[string]interface{}{"amount": in.Amount})
+	if err != nil {
+		return nil, err
+	}
+	tx, balance, err := repo.AdjustBalance(ctx, in.UserID, in.Amount, event)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Admin] admin=%d adjusted user=%d by %d (tx=%d): %s", in.ActorID, in.UserID, in.Amount, tx.ID, reason)
+	return &model.AdjustBalanceOutput{Transaction: tx, Balance: balance}, nil
+}
+
+// validateAdjustment applies the transfer amount bounds to |amount|.
+// MinInt64 has no positive counterpart, and the repo negates debits.
+func validateAdjustment(amount int64) error {
+	if amount == math.MinInt64 {
+		return errors.New("invalid amount: out of range")
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+	return utils.ValidateAmount(amount)
+}
+
+func (s *AdminService) FreezeAccount(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error) {
+	in.Status = model.StatusFrozen
+	return s.SetAccountStatus(ctx, in)
+}
+
+func (s *AdminService) UnfreezeAccount(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error) {
+	in.Status = model.StatusActive
+	return s.SetAccountStatus(ctx, in)
+}
+
+// SetAccountStatus moves an account to in.Status. Closing an account also
+// revokes its session.
+func (s *AdminService) SetAccountStatus(ctx context.Context, in model.SetAccountStatusInput) (*model.User, error) {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	if !isAccountStatus(in.Status) {
+		return nil, ErrInvalidStatus
+	}
+	reason, err := requireReason(in.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := newAuditEvent(ctx, in.ActorID, model.AuditSetAccountStatus, in.UserID, reason, map[string]interface{}{"status": in.Status})
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.SetAccountStatus(ctx, in.UserID, in.Status, reason, s.now().UTC(), event)
+	if err != nil {
+		return nil, err
+	}
+	if in.Status == model.StatusClosed {
+		if err := s.redis.DeleteToken(ctx, in.UserID); err != nil {
+			log.Printf("[Admin] failed to revoke session of closed user=%d: %v", in.UserID, err)
+		}
+	}
+
+	log.Printf("[Admin] admin=%d set user=%d status=%s: %s", in.ActorID, in.UserID, in.Status, reason)
+	return &user, nil
+}
+
+func (s *AdminService) ForceLogout(ctx context.Context, in model.ForceLogoutInput) (*model.ForceLogoutOutput, error) {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	reason, err := requireReason(in.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetUser(ctx, in.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.redis.DeleteToken(ctx, in.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, in.ActorID, model.AuditForceLogout, in.UserID, reason, nil); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Admin] admin=%d forced logout of user=%d: %s", in.ActorID, in.UserID, reason)
+	return &model.ForceLogoutOutput{Success: true}, nil
+}
+
+func (s *AdminService) ListAuditEvents(ctx context.Context, in model.ListAuditEventsInput) (*model.ListAuditEventsOutput, error) {
+	limit, err := pageSize(in.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := parsePageToken(in.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	f := in.Filter
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		return nil, InvalidArgument("INVALID_TIME_RANGE", errors.New("since must be before until"))
+	}
+
+	events, err := s.repo.ListAuditEvents(ctx, f, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{"actor_id": f.ActorID, "target_user_id": f.TargetUserID, "action": f.Action}
+	if err := s.record(ctx, in.ActorID, model.AuditListAuditEvents, 0, "", details); err != nil {
+		return nil, err
+	}
+
+	out := &model.ListAuditEventsOutput{Events: events}
+	if len(events) > limit {
+		out.Events = events[:limit]
+		out.NextPageToken = strconv.FormatInt(out.Events[limit-1].ID, 10)
+	}
+	return out, nil
+}
+
+func (s *AdminService) record(ctx context.Context, actorID int64, action string, targetID int64, reason string, details map[string]interface{}) error {
+	event, err := newAuditEvent(ctx, actorID, action, targetID, reason, details)
+	if err != nil {
+		return err
+	}
+	return s.repo.RecordAuditEvent(ctx, event)
+}
+
+func requireReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", InvalidArgument("REASON_REQUIRED", errors.New("reason is required"))
+	}
+	return reason, nil
+}
+
+func pageSize(size int) (int, error) {
+	switch {
+	case size < 0:
+		return 0, InvalidArgument("INVALID_PAGE_SIZE", errors.New("page size must not be negative"))
+	case size == 0:
+		return defaultAdminPageSize, nil
+	case size > maxAdminPageSize:
+		return maxAdminPageSize, nil
+	}
+	return size, nil
+}
+
+// Page tokens are the ID of the last row on the previous page. They are
+// documented as opaque so the encoding can change.
+func parsePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, InvalidArgument("INVALID_PAGE_TOKEN", errors.New("invalid page token"))
+	}
+	return id, nil
+}
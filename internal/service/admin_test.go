@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTokens(t *testing.T, userIDs ...int64) *repo.MemoryTokenStore {
+	tokens := repo.NewMemoryTokenStore()
+	for _, id := range userIDs {
+		require.NoError(t, tokens.SaveToken(context.Background(), id, "token", 0))
+	}
+	return tokens
+}
+
+func tokenOf(t *testing.T, tokens *repo.MemoryTokenStore, userID int64) string {
+	t.Helper()
+	token, err := tokens.GetToken(context.Background(), userID)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAdminService_AdjustBalance(t *testing.T) {
+	store := repotest.NewTransferRepo(t, model.User{ID: 1, Name: "alice", Balance: 100})
+	svc := service.NewAdminService(store, newTestTokens(t))
+	ctx := context.Background()
+
+	_, err := svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: 50, Reason: "  "})
+	require.ErrorIs(t, err, &service.Error{Reason: "REASON_REQUIRED"})
+	for _, amount := range []int64{0, 1_000_000_000, -1_000_000_000, math.MaxInt64, math.MinInt64} {
+		_, err = svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: amount, Reason: "refund"})
+		require.ErrorIs(t, err, &service.Error{Reason: "INVALID_AMOUNT"}, "amount %d", amount)
+	}
+	require.Empty(t, auditEvents(t, store))
+
+	out, err := svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: 50, Reason: "refund #12"})
+	require.NoError(t, err)
+	require.EqualValues(t, 150, out.Balance)
+	require.Equal(t, model.SystemAccountID, out.Transaction.From)
+
+	out, err = svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: -30, Reason: "chargeback"})
+	require.NoError(t, err)
+	require.EqualValues(t, 120, out.Balance)
+	require.Equal(t, model.SystemAccountID, out.Transaction.To)
+	require.EqualValues(t, -20, balanceOf(t, store, model.SystemAccountID))
+
+	events := auditEvents(t, store)
+	require.Len(t, events, 2)
+	event := events[1]
+	require.Equal(t, model.AuditAdjustBalance, event.Action)
+	require.EqualValues(t, 9, event.ActorID)
+	require.EqualValues(t, 1, event.TargetUserID)
+	require.Equal(t, "chargeback", event.Reason)
+	var details map[string]int64
+	require.NoError(t, json.Unmarshal([]byte(event.Details), &details))
+	require.EqualValues(t, -30, details["amount"])
+
+	out, err = svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: 999_999_999, Reason: "largest credit"})
+	require.NoError(t, err)
+	require.EqualValues(t, 1_000_000_119, out.Balance)
+	out, err = svc.AdjustBalance(ctx, model.AdjustBalanceInput{ActorID: 9, UserID: 1, Amount: -999_999_999, Reason: "largest debit"})
+	require.NoError(t, err)
+	require.EqualValues(t, 120, out.Balance)
+}
+
+func TestAdminService_AccountStatus(t *testing.T) {
+	store := repotest.NewTransferRepo(t,
+		model.User{ID: 1, Name: "alice", Balance: 10, Status: model.StatusActive},
+		model.User{ID: 2, Name: "bob", Status: model.StatusActive},
+	)
+	tokens := newTestTokens(t, 1, 2)
+	svc := service.NewAdminService(store, tokens)
+	ctx := context.Background()
+
+	user, err := svc.FreezeAccount(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 1, Reason: "fraud review"})
+	require.NoError(t, err)
+	require.Equal(t, model.StatusFrozen, user.Status)
+	require.Equal(t, "fraud review", user.StatusReason)
+	require.NotNil(t, user.StatusChangedAt)
+
+	user, err = svc.UnfreezeAccount(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 1, Reason: "cleared"})
+	require.NoError(t, err)
+	require.Equal(t, model.StatusActive, user.Status)
+
+	_, err = svc.SetAccountStatus(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 1, Status: "gone", Reason: "x"})
+	require.ErrorIs(t, err, service.ErrInvalidStatus)
+	_, err = svc.SetAccountStatus(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 1, Status: model.StatusClosed, Reason: "x"})
+	require.ErrorIs(t, err, service.ErrBalanceNotZero)
+	_, err = svc.FreezeAccount(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 3, Reason: "x"})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+
+	// Closing revokes the session and is final.
+	_, err = svc.SetAccountStatus(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 2, Status: model.StatusClosed, Reason: "customer request"})
+	require.NoError(t, err)
+	require.Empty(t, tokenOf(t, tokens, 2))
+	require.NotEmpty(t, tokenOf(t, tokens, 1))
+	_, err = svc.UnfreezeAccount(ctx, model.SetAccountStatusInput{ActorID: 9, UserID: 2, Reason: "reopen"})
+	require.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+	events := auditEvents(t, store)
+	require.Len(t, events, 3)
+	for _, e := range events {
+		require.Equal(t, model.AuditSetAccountStatus, e.Action)
+	}
+}
+
+func TestAdminService_ForceLogout(t *testing.T) {
+	store := repotest.NewTransferRepo(t, model.User{ID: 1, Name: "alice", Status: model.StatusActive})
+	tokens := newTestTokens(t, 1)
+	svc := service.NewAdminService(store, tokens)
+	ctx := context.Background()
+
+	_, err := svc.ForceLogout(ctx, model.ForceLogoutInput{ActorID: 9, UserID: 1})
+	require.ErrorIs(t, err, &service.Error{Reason: "REASON_REQUIRED"})
+
+	out, err := svc.ForceLogout(ctx, model.ForceLogoutInput{ActorID: 9, UserID: 1, Reason: "compromised"})
+	require.NoError(t, err)
+	require.True(t, out.Success)
+	require.Empty(t, tokenOf(t, tokens, 1))
+	events := auditEvents(t, store)
+	require.Len(t, events, 1)
+	require.Equal(t, model.AuditForceLogout, events[0].Action)
+}
+
+func TestAdminService_ListUsersPagination(t *testing.T) {
+	var users []model.User
+	for id := int64(1); id <= 5; id++ {
+		users = append(users, model.User{ID: id})
+	}
+	store := repotest.NewTransferRepo(t, users...)
+	svc := service.NewAdminService(store, newTestTokens(t))
+	ctx := context.Background()
+
+	var seen []int64
+	token := ""
+	for {
+		out, err := svc.ListUsers(ctx, model.ListUsersInput{ActorID: 9, PageSize: 2, PageToken: token})
+		require.NoError(t, err)
+		for _, u := range out.Users {
+			seen = append(seen, u.ID)
+		}
+		if out.NextPageToken == "" {
+			break
+		}
+		token = out.NextPageToken
+	}
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, seen)
+	require.Len(t, auditEvents(t, store), 3)
+
+	_, err := svc.ListUsers(ctx, model.ListUsersInput{PageToken: "abc"})
+	require.ErrorIs(t, err, &service.Error{Reason: "INVALID_PAGE_TOKEN"})
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"project/internal/model"
+)
+
+// API keys look like tk_<prefix>.<secret>. The prefix is stored in clear to
+// find the key; only a SHA-256 of the secret is stored.
+const apiKeyMarker = "tk_"
+
+var serviceAccountName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+type APIKeyRepo interface {
+	EnsureServiceAccount(ctx context.Context, ownerID int64, name string) (model.ServiceAccount, error)
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	ListAPIKeys(ctx context.Context, ownerID int64) ([]model.APIKeyRecord, error)
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKeyRecord, error)
+	RevokeAPIKey(ctx context.Context, ownerID, id int64, at time.Time) error
+	TouchAPIKey(ctx context.Context, id int64, at time.Time) error
+}
+
+type APIKeyService struct {
+	repo  APIKeyRepo
+	audit AuditRecorder
+	now   func() time.Time
+}
+
+func NewAPIKeyService(repo APIKeyRepo, audit AuditRecorder) *APIKeyService {
+	return &APIKeyService{repo: repo, audit: audit, now: time.Now}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, in model.CreateAPIKeyInput) (*model.CreateAPIKeyOutput, error) {
+	if !serviceAccountName.MatchString(in.ServiceAccount) {
+		return nil, InvalidArgument("INVALID_SERVICE_ACCOUNT", errors.New("service account name must be 1-64 lowercase letters, digits, '-' or '_'"))
+	}
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if in.TTL < 0 {
+		return nil, InvalidArgument("INVALID_TTL", errors.New("ttl must not be negative"))
+	}
+
+	account, err := s.repo.EnsureServiceAccount(ctx, in.OwnerID, in.ServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	key := model.APIKey{
+		ServiceAccountID: account.ID,
+		Prefix:           prefix,
+		SecretHash:       hashAPIKeySecret(secret),
+		Scopes:           strings.Join(scopes, " "),
+	}
+	if in.TTL > 0 {
+		expires := s.now().Add(in.TTL).UTC()
+		key.ExpiresAt = &expires
+	}
+	if err := s.repo.CreateAPIKey(ctx, &key); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, in.OwnerID, model.AuditAPIKeyCreate, in.OwnerID, map[string]interface{}{
+		"key_id":          key.ID,
+		"prefix":          prefix,
+		"service_account": account.Name,
+		"scopes":          scopes,
+		"expires_at":      key.ExpiresAt,
+	})
+	log.Printf("[APIKey] user=%d created key id=%d for service account %q scopes=%v", in.OwnerID, key.ID, account.Name, scopes)
+	return &model.CreateAPIKeyOutput{
+		Key:    model.APIKeyRecord{APIKey: key, ServiceAccountName: account.Name, OwnerID: in.OwnerID},
+		Secret: apiKeyMarker + prefix + "." + secret,
+	}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, in model.ListAPIKeysInput) (*model.ListAPIKeysOutput, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, in.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ListAPIKeysOutput{Keys: keys}, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, in model.RevokeAPIKeyInput) (*model.RevokeAPIKeyOutput, error) {
+	if err := s.repo.RevokeAPIKey(ctx, in.OwnerID, in.ID, s.now().UTC()); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.audit, in.OwnerID, model.AuditAPIKeyRevoke, in.OwnerID, map[string]interface{}{"key_id": in.ID})
+	log.Printf("[APIKey] user=%d revoked key id=%d", in.OwnerID, in.ID)
+	return &model.RevokeAPIKeyOutput{Success: true}, nil
+}
+
+// AuthenticateAPIKey resolves a presented key to the principal it acts as.
+// Every failure returns the same error so callers cannot probe which part of
+// a key was wrong.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, presented string) (*model.APIKeyPrincipal, error) {
+	prefix, secret, ok := splitAPIKey(presented)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+	key, err := s.repo.FindAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, errInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashAPIKeySecret(secret))) != 1 {
+		return nil, errInvalidAPIKey
+	}
+	now := s.now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return nil, errInvalidAPIKey
+	}
+
+	if err := s.repo.TouchAPIKey(ctx, key.ID, now.UTC()); err != nil {
+		log.Printf("[APIKey] failed to record last use of key id=%d: %v", key.ID, err)
+	}
+	return &model.APIKeyPrincipal{
+		UserID:           key.OwnerID,
+		ServiceAccountID: key.ServiceAccountID,
+		KeyID:            key.ID,
+		Scopes:           key.ScopeList(),
+	}, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, InvalidArgument("INVALID_SCOPE", errors.New("at least one scope is required"))
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, scope := range scopes {
+		known := false
+		for _, k := range model.KnownScopes {
+			if scope == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, InvalidArgument("INVALID_SCOPE", fmt.Errorf("unknown scope %q", scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			out = append(out, scope)
+		}
+	}
+	return out, nil
+}
+
+func newAPIKeySecret() (prefix, secret string, err error) {
+	p := make([]byte, 6)
+	s := make([]byte, 32)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(s); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(p), base64.RawURLEncoding.EncodeToString(s), nil
+}
+
+func splitAPIKey(key string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(key, apiKeyMarker)
+	if !found {
+		return "", "", false
+	}
+	prefix, secret, ok = strings.Cut(rest, ".")
+	return prefix, secret, ok && prefix != "" && secret != ""
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func apiKeyOf(t *testing.T, keys *repo.MemoryAPIKeyRepo, ownerID, id int64) model.APIKeyRecord {
+	t.Helper()
+	records, err := keys.ListAPIKeys(context.Background(), ownerID)
+	require.NoError(t, err)
+	for _, r := range records {
+		if r.ID == id {
+			return r
+		}
+	}
+	t.Fatalf("api key %d of user %d not found", id, ownerID)
+	return model.APIKeyRecord{}
+}
+
+func TestAPIKeyService_CreateAuthenticateRevoke(t *testing.T) {
+	keys := repo.NewMemoryAPIKeyRepo()
+	store := repotest.NewTransferRepo(t)
+	svc := service.NewAPIKeyService(keys, store)
+	ctx := context.Background()
+
+	out, err := svc.CreateAPIKey(ctx, model.CreateAPIKeyInput{
+		OwnerID:        7,
+		ServiceAccount: "payouts",
+		Scopes:         []string{model.ScopeTransferSend, model.ScopeTransferSend},
+	})
+	require.NoError(t, err)
+	require.NotContains(t, apiKeyOf(t, keys, 7, out.Key.ID).SecretHash, out.Secret)
+
+	principal, err := svc.AuthenticateAPIKey(ctx, out.Secret)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), principal.UserID)
+	require.Equal(t, []string{model.ScopeTransferSend}, principal.Scopes)
+	require.NotNil(t, apiKeyOf(t, keys, 7, out.Key.ID).LastUsedAt)
+
+	_, err = svc.AuthenticateAPIKey(ctx, out.Secret+"x")
+	require.Error(t, err)
+
+	_, err = svc.RevokeAPIKey(ctx, model.RevokeAPIKeyInput{OwnerID: 8, ID: out.Key.ID})
+	require.ErrorIs(t, err, service.ErrAPIKeyNotFound)
+	_, err = svc.RevokeAPIKey(ctx, model.RevokeAPIKeyInput{OwnerID: 7, ID: out.Key.ID})
+	require.NoError(t, err)
+	_, err = svc.AuthenticateAPIKey(ctx, out.Secret)
+	require.Error(t, err)
+
+	events := auditEvents(t, store)
+	require.Len(t, events, 2)
+	require.Equal(t, model.AuditAPIKeyCreate, events[0].Action)
+	require.NotContains(t, events[0].Details, out.Secret)
+	require.Equal(t, model.AuditAPIKeyRevoke, events[1].Action)
+}
+
+func TestAPIKeyService_Expiry(t *testing.T) {
+	svc := service.NewAPIKeyService(repo.NewMemoryAPIKeyRepo(), repotest.NewTransferRepo(t))
+	now := time.Now()
+	svc.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	out, err := svc.CreateAPIKey(ctx, model.CreateAPIKeyInput{
+		OwnerID: 7, ServiceAccount: "payouts", Scopes: []string{model.ScopeTransferRead}, TTL: time.Hour,
+	})
+	require.NoError(t, err)
+	_, err = svc.AuthenticateAPIKey(ctx, out.Secret)
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = svc.AuthenticateAPIKey(ctx, out.Secret)
+	require.Error(t, err)
+}
+
+func TestAPIKeyService_RejectsUnknownScope(t *testing.T) {
+	svc := service.NewAPIKeyService(repo.NewMemoryAPIKeyRepo(), repotest.NewTransferRepo(t))
+	_, err := svc.CreateAPIKey(context.Background(), model.CreateAPIKeyInput{
+		OwnerID: 7, ServiceAccount: "payouts", Scopes: []string{"admin:everything"},
+	})
+	e, ok := service.AsError(err)
+	require.True(t, ok)
+	require.Equal(t, "INVALID_SCOPE", e.Reason)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"project/internal/model"
+)
+
+type AuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, event model.AuditEvent) error
+}
+
+type AuditScanner interface {
+	ScanAuditEvents(ctx context.Context, afterID int64, limit int) ([]model.AuditEvent, error)
+}
+
+// newAuditEvent builds an event for action, copying the caller's IP, user
+// agent and request ID from ctx.
+func newAuditEvent(ctx context.Context, actorID int64, action string, targetID int64, reason string, details map[string]interface{}) (model.AuditEvent, error) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return model.AuditEvent{}, err
+	}
+	info := model.RequestInfoFromContext(ctx)
+	return model.AuditEvent{
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetID,
+		Reason:       reason,
+		Details:      string(data),
+		IP:           info.IP,
+		UserAgent:    info.UserAgent,
+		RequestID:    info.RequestID,
+	}, nil
+}
+
+// recordAudit is for actions that have already taken effect: a failure to
+// write the event is logged rather than returned.
+func recordAudit(ctx context.Context, rec AuditRecorder, actorID int64, action string, targetID int64, details map[string]interface{}) {
+	event, err := newAuditEvent(ctx, actorID, action, targetID, "", details)
+	if err == nil {
+		err = rec.RecordAuditEvent(ctx, event)
+	}
+	if err != nil {
+		log.Printf("[Audit] failed to record %s by user=%d: %v", action, actorID, err)
+	}
+}
+
+// VerifyAuditChain walks the audit log in order and checks every entry's
+// link to its predecessor and its own hash. It stops at the first broken
+// entry and returns an error naming it.
+func VerifyAuditChain(ctx context.Context, scanner AuditScanner, batchSize int) (model.AuditVerifyResult, error) {
+	var res model.AuditVerifyResult
+	prevHash := model.AuditGenesisHash
+	var afterID int64
+	for {
+		events, err := scanner.ScanAuditEvents(ctx, afterID, batchSize)
+		if err != nil {
+			return res, err
+		}
+		if len(events) == 0 {
+			return res, nil
+		}
+		for _, e := range events {
+			if e.PrevHash != prevHash {
+				return res, fmt.Errorf("audit event %d: prev_hash does not match event %d; an entry was removed or reordered", e.ID, res.LastID)
+			}
+			if e.ComputeHash(prevHash) != e.Hash {
+				return res, fmt.Errorf("audit event %d: hash mismatch; the entry was modified", e.ID)
+			}
+			prevHash = e.Hash
+			res.Checked++
+			res.LastID = e.ID
+			res.LastHash = e.Hash
+		}
+		afterID = events[len(events)-1].ID
+	}
+}
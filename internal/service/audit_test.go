@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"project/internal/model"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tamperedLog serves a stored audit log with edit applied to every scan, as
+// if rows had been changed in the database.
+type tamperedLog struct {
+	service.AuditScanner
+	edit func(events []model.AuditEvent) []model.AuditEvent
+}
+
+func (l tamperedLog) ScanAuditEvents(ctx context.Context, afterID int64, limit int) ([]model.AuditEvent, error) {
+	events, err := l.AuditScanner.ScanAuditEvents(ctx, 0, 10000)
+	if err != nil {
+		return nil, err
+	}
+	var out []model.AuditEvent
+	for _, e := range l.edit(events) {
+		if e.ID > afterID && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	store := repotest.NewTransferRepo(t)
+	ctx := model.WithRequestInfo(context.Background(), model.RequestInfo{IP: "10.0.0.1", UserAgent: "curl/8", RequestID: "req-1"})
+	for i := int64(1); i <= 5; i++ {
+		service.RecordAudit(ctx, store, i, model.AuditLogin, i, nil)
+	}
+	events := auditEvents(t, store)
+	require.Equal(t, "10.0.0.1", events[0].IP)
+	require.Equal(t, "curl/8", events[0].UserAgent)
+	require.Equal(t, "req-1", events[0].RequestID)
+	require.Equal(t, model.AuditGenesisHash, events[0].PrevHash)
+
+	res, err := service.VerifyAuditChain(context.Background(), store, 2)
+	require.NoError(t, err)
+	require.Equal(t, 5, res.Checked)
+	require.EqualValues(t, 5, res.LastID)
+
+	// A modified entry fails its own hash.
+	modified := tamperedLog{AuditScanner: store, edit: func(events []model.AuditEvent) []model.AuditEvent {
+		events[2].TargetUserID = 99
+		return events
+	}}
+	_, err = service.VerifyAuditChain(context.Background(), modified, 2)
+	require.ErrorContains(t, err, "audit event 3: hash mismatch")
+
+	// A removed entry breaks the next entry's link.
+	removed := tamperedLog{AuditScanner: store, edit: func(events []model.AuditEvent) []model.AuditEvent {
+		return append(events[:1], events[2:]...)
+	}}
+	_, err = service.VerifyAuditChain(context.Background(), removed, 2)
+	require.ErrorContains(t, err, "audit event 3: prev_hash")
+}
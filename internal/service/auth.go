@@ -7,30 +7,37 @@ import (
 	"project/internal/model"
 	"project/internal/utils"
 	"time"
-
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type DBClient interface {
 	GetPassword(ctx context.Context, userID int64) (string, error)
+	GetRoles(ctx context.Context, userID int64) ([]string, error)
+	GetAccountStatus(ctx context.Context, userID int64) (string, error)
 }
 
 type RedisClient interface {
 	SaveToken(ctx context.Context, userID int64, token string, ttl time.Duration) error
 	DeleteToken(ctx context.Context, userID int64) error
 }
+type TokenIssuer interface {
+	GenerateAccessToken(userID int64, roles []string, accessTokenTTL time.Duration) (string, error)
+}
+
 type AuthService struct {
 	db     DBClient
 	config *config.Config
 	redis  RedisClient
+	tokens TokenIssuer
+	audit  AuditRecorder
 }
 
-func NewAuthService(config *config.Config, db DBClient, redis RedisClient) *AuthService {
+func NewAuthService(config *config.Config, db DBClient, redis RedisClient, tokens TokenIssuer, audit AuditRecorder) *AuthService {
 	return &AuthService{
 		db:     db,
 		config: config,
 		redis:  redis,
+		tokens: tokens,
+		audit:  audit,
 	}
 }
 
@@ -44,29 +51,49 @@ func (a *AuthService) GetUserID(ctx context.Context) int64 {
 func (a *AuthService) Login(ctx context.Context, req model.LoginInput) (*model.LoginOutput, error) {
 
 	if err := utils.ValidateUserID(req.Username); err != nil {
-		return nil, err
+		return nil, InvalidArgument("INVALID_USER_ID", err)
 	}
 
 	pass, err := a.db.GetPassword(ctx, req.Username)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+		recordAudit(ctx, a.audit, 0, model.AuditLoginFailed, req.Username, map[string]interface{}{"cause": "unknown_user"})
+		return nil, ErrInvalidCredentials
 	}
 
 	check := utils.CheckPassword(pass, req.Password)
 	if !check {
-		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+		recordAudit(ctx, a.audit, 0, model.AuditLoginFailed, req.Username, map[string]interface{}{"cause": "bad_password"})
+		return nil, ErrInvalidCredentials
+	}
+
+	accountStatus, err := a.db.GetAccountStatus(ctx, req.Username)
+	if err != nil {
+		log.Printf("[Login] load status for user=%d failed: %v", req.Username, err)
+		return nil, ErrInternal
+	}
+	if accountStatus == model.StatusClosed {
+		recordAudit(ctx, a.audit, 0, model.AuditLoginFailed, req.Username, map[string]interface{}{"cause": "account_closed"})
+		return nil, ErrAccountClosed
+	}
+
+	roles, err := a.db.GetRoles(ctx, req.Username)
+	if err != nil {
+		log.Printf("[Login] load roles for user=%d failed: %v", req.Username, err)
+		return nil, ErrInternal
 	}
 
-	accessToken, err := utils.GenerateAccessToken(req.Username, a.config.JWT.AccessTokenTTL, a.config.JWT.AccessSecret)
+	accessToken, err := a.tokens.GenerateAccessToken(req.Username, roles, a.config.JWT.AccessTokenTTL)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate access token: %v", err)
+		log.Printf("[Login] generate access token for user=%d failed: %v", req.Username, err)
+		return nil, ErrInternal
 	}
 
 	if err := a.redis.SaveToken(ctx, req.Username, accessToken, a.config.JWT.AccessTokenTTL); err != nil {
 		log.Printf("[Login] Redis save token failed: %v", err)
-		return nil, status.Error(codes.Internal, "internal server error")
+		return nil, ErrInternal
 	}
 
+	recordAudit(ctx, a.audit, req.Username, model.AuditLogin, req.Username, nil)
 	log.Printf("[Login] success user=%d ", req.Username)
 	return &model.LoginOutput{AccessToken: accessToken}, nil
 }
@@ -76,9 +103,10 @@ func (a *AuthService) Logout(ctx context.Context, in model.LogoutInput) (*model.
 	err := a.redis.DeleteToken(ctx, userID)
 	if err != nil {
 		log.Printf("[Logout] failed to remove token for user=%d: %v", userID, err)
-		return nil, status.Error(codes.Internal, "failed to logout")
+		return nil, ErrInternal
 	}
 
+	recordAudit(ctx, a.audit, userID, model.AuditLogout, userID, nil)
 	log.Printf("[Logout] user=%d logged out successfully", userID)
 	return &model.LogoutOutput{Success: true}, nil
 }
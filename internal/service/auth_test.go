@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memTokenIssuer implements TokenIssuer with readable, unsigned tokens.
+type memTokenIssuer struct{}
+
+func (memTokenIssuer) GenerateAccessToken(userID int64, roles []string, accessTokenTTL time.Duration) (string, error) {
+	return fmt.Sprintf("token-%d-%s", userID, strings.Join(roles, ",")), nil
+}
+
+func newTestAuthService(t *testing.T) (*service.AuthService, *repo.MemoryTransferRepo, *repo.MemoryTokenStore, *config.Config) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	store := repotest.NewTransferRepo(t,
+		model.User{ID: 1, Name: "alice", Password: string(hash), Roles: "user admin", Status: model.StatusActive},
+		model.User{ID: 2, Name: "bob", Password: string(hash), Roles: "user", Status: model.StatusFrozen},
+		model.User{ID: 3, Name: "carol", Password: string(hash), Status: model.StatusClosed},
+	)
+	cfg := config.LoadConfig()
+	tokens := repo.NewMemoryTokenStore()
+	return service.NewAuthService(cfg, store, tokens, memTokenIssuer{}, store), store, tokens, cfg
+}
+
+func TestAuthService_Login(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        model.LoginInput
+		wantToken string
+		wantErr   error
+		cause     string
+	}{
+		{name: "ok", in: model.LoginInput{Username: 1, Password: "password123"}, wantToken: "token-1-user,admin"},
+		{name: "frozen accounts can log in", in: model.LoginInput{Username: 2, Password: "password123"}, wantToken: "token-2-user"},
+		{name: "invalid id", in: model.LoginInput{Username: 0, Password: "password123"}, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "unknown user", in: model.LoginInput{Username: 99, Password: "password123"}, wantErr: service.ErrInvalidCredentials, cause: "unknown_user"},
+		{name: "bad password", in: model.LoginInput{Username: 1, Password: "password124"}, wantErr: service.ErrInvalidCredentials, cause: "bad_password"},
+		{name: "closed account", in: model.LoginInput{Username: 3, Password: "password123"}, wantErr: service.ErrAccountClosed, cause: "account_closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, tokens, _ := newTestAuthService(t)
+
+			out, err := svc.Login(context.Background(), tt.in)
+			events := auditEvents(t, store)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantToken, out.AccessToken)
+				require.Equal(t, tt.wantToken, tokenOf(t, tokens, tt.in.Username))
+				require.Len(t, events, 1)
+				require.Equal(t, model.AuditLogin, events[0].Action)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			for id := int64(1); id <= 3; id++ {
+				require.Empty(t, tokenOf(t, tokens, id))
+			}
+			if tt.cause == "" {
+				require.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			require.Equal(t, model.AuditLoginFailed, events[0].Action)
+			require.Equal(t, tt.in.Username, events[0].TargetUserID)
+			require.Contains(t, events[0].Details, tt.cause)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	svc, store, tokens, cfg := newTestAuthService(t)
+	ctx := context.WithValue(context.Background(), cfg.UserIDKey, int64(1))
+	require.NoError(t, tokens.SaveToken(ctx, 1, "token-1-user,admin", 0))
+	require.NoError(t, tokens.SaveToken(ctx, 2, "token-2-user", 0))
+
+	out, err := svc.Logout(ctx, model.LogoutInput{UserID: 1})
+	require.NoError(t, err)
+	require.True(t, out.Success)
+	require.Empty(t, tokenOf(t, tokens, 1))
+	require.Equal(t, "token-2-user", tokenOf(t, tokens, 2))
+	events := auditEvents(t, store)
+	require.Len(t, events, 1)
+	require.Equal(t, model.AuditLogout, events[0].Action)
+	require.EqualValues(t, 1, events[0].ActorID)
+}
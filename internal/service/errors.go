@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrorKind classifies a domain error. The gRPC layer maps each kind to a
+// status code; nothing below it needs to know about transport codes.
+type ErrorKind int
+
+const (
+	KindInvalidArgument ErrorKind = iota + 1
+	KindNotFound
+	KindFailedPrecondition
+	KindPermissionDenied
+	KindUnauthenticated
+	KindInternal
+)
+
+// Error is a domain error. Reason is a stable UPPER_SNAKE_CASE code that
+// clients can switch on; Message is for humans and may change.
+type Error struct {
+	Kind     ErrorKind
+	Reason   string
+	Message  string
+	Metadata map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is matches on Reason, so errors.Is(err, ErrInsufficientFunds) holds for any
+// error carrying that reason regardless of message or metadata.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+var (
+	ErrInsufficientFunds = &Error{Kind: KindFailedPrecondition, Reason: "INSUFFICIENT_FUNDS", Message: "insufficient balance"}
+	ErrSelfTransfer      = &Error{Kind: KindInvalidArgument, Reason: "SELF_TRANSFER", Message: "cannot transfer to yourself"}
+	ErrUserNotFound      = &Error{Kind: KindNotFound, Reason: "USER_NOT_FOUND", Message: "user not found"}
+	ErrAPIKeyNotFound    = &Error{Kind: KindNotFound, Reason: "API_KEY_NOT_FOUND", Message: "api key not found"}
+	ErrAccountClosed     = &Error{Kind: KindPermissionDenied, Reason: "ACCOUNT_CLOSED", Message: "account is closed"}
+
+	ErrInvalidCredentials = &Error{Kind: KindUnauthenticated, Reason: "INVALID_CREDENTIALS", Message: "invalid username or password"}
+	// ErrInternal is for failures whose cause is logged, not shown.
+	ErrInternal = &Error{Kind: KindInternal, Reason: "INTERNAL", Message: "internal server error"}
+
+	ErrSenderFrozen      = &Error{Kind: KindFailedPrecondition, Reason: "SENDER_ACCOUNT_FROZEN", Message: "sender account is frozen"}
+	ErrSenderClosed      = &Error{Kind: KindFailedPrecondition, Reason: "SENDER_ACCOUNT_CLOSED", Message: "sender account is closed"}
+	ErrSenderReceiveOnly = &Error{Kind: KindFailedPrecondition, Reason: "SENDER_ACCOUNT_RECEIVE_ONLY", Message: "sender account can only receive"}
+	ErrRecipientFrozen   = &Error{Kind: KindFailedPrecondition, Reason: "RECIPIENT_ACCOUNT_FROZEN", Message: "recipient account is frozen"}
+	ErrRecipientClosed   = &Error{Kind: KindFailedPrecondition, Reason: "RECIPIENT_ACCOUNT_CLOSED", Message: "recipient account is closed"}
+
+	ErrInvalidStatus           = &Error{Kind: KindInvalidArgument, Reason: "INVALID_STATUS", Message: "unknown account status"}
+	ErrInvalidStatusTransition = &Error{Kind: KindFailedPrecondition, Reason: "INVALID_STATUS_TRANSITION", Message: "account status cannot change that way"}
+	ErrBalanceNotZero          = &Error{Kind: KindFailedPrecondition, Reason: "BALANCE_NOT_ZERO", Message: "account balance must be zero to close it"}
+)
+
+func UserNotFound(userID int64) error {
+	return &Error{
+		Kind:     KindNotFound,
+		Reason:   ErrUserNotFound.Reason,
+		Message:  fmt.Sprintf("user %d not found", userID),
+		Metadata: map[string]string{"user_id": fmt.Sprint(userID)},
+	}
+}
+
+// LineError marks a domain error as caused by line (0-based) of a batch
+// request; other errors are returned unchanged.
+func LineError(line int, err error) error {
+	e, ok := AsError(err)
+	if !ok {
+		return err
+	}
+	out := *e
+	out.Message = fmt.Sprintf("line %d: %s", line, e.Message)
+	out.Metadata = map[string]string{"line": strconv.Itoa(line)}
+	for k, v := range e.Metadata {
+		out.Metadata[k] = v
+	}
+	return &out
+}
+
+func InvalidArgument(reason string, err error) error {
+	return &Error{Kind: KindInvalidArgument, Reason: reason, Message: err.Error()}
+}
+
+// AsError returns the domain error wrapped in err, if any.
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"project/config"
+	"project/internal/model"
+	"time"
+)
+
+// eventLeaseTTL bounds how long a claimed event blocks redeliveries if the
+// consumer dies mid-handling.
+const eventLeaseTTL = time.Minute
+
+var duplicateEventsDropped = expvar.NewInt("consumer_duplicate_events_dropped")
+
+var errEventInFlight = errors.New("event is being handled by another consumer")
+
+type EventStore interface {
+	ClaimEvent(ctx context.Context, eventID string, ttl time.Duration) (bool, error)
+	IsEventProcessed(ctx context.Context, eventID string) (bool, error)
+	MarkEventProcessed(ctx context.Context, eventID string, ttl time.Duration) error
+	ReleaseEvent(ctx context.Context, eventID string) error
+}
+
+type EventConsumer struct {
+	store  EventStore
+	config *config.Config
+}
+
+func NewEventConsumer(config *config.Config, store EventStore) *EventConsumer {
+	return &EventConsumer{
+		store:  store,
+		config: config,
+	}
+}
+
+// Handle decodes and processes one event. Events whose ID was already
+// processed within the dedup window are dropped, so the handlers below see
+// each event effectively once even though Pub/Sub delivers at least once.
+func (c *EventConsumer) Handle(ctx context.Context, data []byte) error {
+	event, err := model.DecodeEvent(data)
+	if err != nil {
+		return fmt.Errorf("decode event: %w", err)
+	}
+
+	claimed, err := c.store.ClaimEvent(ctx, event.ID, eventLeaseTTL)
+	if err != nil {
+		return fmt.Errorf("claim event %s: %w", event.ID, err)
+	}
+	if !claimed {
+		done, err := c.store.IsEventProcessed(ctx, event.ID)
+		if err != nil {
+			return fmt.Errorf("check event %s: %w", event.ID, err)
+		}
+		if !done {
+			return errEventInFlight
+		}
+		duplicateEventsDropped.Add(1)
+		log.Printf("[Consumer] dropped duplicate %s id=%s", event.Type, event.ID)
+		return nil
+	}
+
+	if err := c.process(ctx, event); err != nil {
+		if relErr := c.store.ReleaseEvent(ctx, event.ID); relErr != nil {
+			log.Printf("[Consumer] release event %s failed: %v", event.ID, relErr)
+		}
+		return err
+	}
+
+	if err := c.store.MarkEventProcessed(ctx, event.ID, c.config.PubSub.DedupTTL); err != nil {
+		log.Printf("[Consumer] mark event %s processed failed: %v", event.ID, err)
+	}
+	return nil
+}
+
+func (c *EventConsumer) process(ctx context.Context, event model.Event) error {
+	log.Printf("[Consumer] %s id=%s occurred_at=%s data=%s", event.Type, event.ID, event.OccurredAt.Format(time.RFC3339), string(event.Data))
+	return nil
+}
@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventConsumer_DropsDuplicates(t *testing.T) {
+	c := service.NewEventConsumer(&config.Config{PubSub: config.PubSubConfig{DedupTTL: time.Hour}}, repo.NewMemoryTokenStore())
+
+	event, err := model.NewEvent(model.EventTransferCompleted, model.TransferCompleted{From: 1, To: 2, Amount: 5})
+	require.NoError(t, err)
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	before := service.DuplicateEventsDropped.Value()
+	require.NoError(t, c.Handle(context.Background(), data))
+	require.NoError(t, c.Handle(context.Background(), data))
+	require.Equal(t, before+1, service.DuplicateEventsDropped.Value())
+}
+
+func TestEventConsumer_InFlightIsRetried(t *testing.T) {
+	store := repo.NewMemoryTokenStore()
+	c := service.NewEventConsumer(&config.Config{}, store)
+
+	event, err := model.NewEvent(model.EventTransferCompleted, model.TransferCompleted{From: 1, To: 2, Amount: 5})
+	require.NoError(t, err)
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	// Another consumer holds the event.
+	claimed, err := store.ClaimEvent(context.Background(), event.ID, time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.ErrorIs(t, c.Handle(context.Background(), data), service.ErrEventInFlight)
+}
+
+func TestEventConsumer_RejectsInvalidPayload(t *testing.T) {
+	c := service.NewEventConsumer(&config.Config{}, repo.NewMemoryTokenStore())
+	require.Error(t, c.Handle(context.Background(), []byte(`{"from":"1","to":"2","amount":5}`)))
+}
@@ -0,0 +1,12 @@
+package service
+
+import "time"
+
+// Internals used by the tests in package service_test.
+var (
+	ErrEventInFlight       = errEventInFlight
+	DuplicateEventsDropped = duplicateEventsDropped
+	RecordAudit            = recordAudit
+)
+
+func (s *APIKeyService) SetClock(now func() time.Time) { s.now = now }
@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"project/internal/model"
+	"project/internal/repo"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The service tests that need storage run against the in-memory repositories
+// the dev command and API tests use, seeded with repotest.NewTransferRepo.
+// They live in package service_test because repo imports service.
+
+// auditEvents returns every recorded event, oldest first.
+func auditEvents(t *testing.T, store *repo.MemoryTransferRepo) []model.AuditEvent {
+	events, err := store.ScanAuditEvents(context.Background(), 0, 10000)
+	require.NoError(t, err)
+	return events
+}
+
+// transactions returns every recorded transaction, oldest first.
+func transactions(t *testing.T, store *repo.MemoryTransferRepo) []model.Transaction {
+	txs, err := store.ScanTransactions(context.Background(), 0, model.TransactionScan{}, 10000)
+	require.NoError(t, err)
+	return txs
+}
+
+func balanceOf(t *testing.T, store *repo.MemoryTransferRepo, userID int64) int64 {
+	t.Helper()
+	balance, err := store.GetBalance(context.Background(), userID)
+	require.NoError(t, err)
+	return balance
+}
+
+func userOf(t *testing.T, store *repo.MemoryTransferRepo, userID int64) model.User {
+	t.Helper()
+	u, err := store.GetUser(context.Background(), userID)
+	require.NoError(t, err)
+	return u
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"project/config"
 	"project/internal/model"
 	"project/internal/utils"
 )
@@ -11,23 +13,26 @@ type TransferRepo interface {
 	ListTransactions(ctx context.Context, from int64) ([]model.Transaction, error)
 	GetBalance(ctx context.Context, userID int64) (int64, error)
 	GetPassword(ctx context.Context, userID int64) (string, error)
-	InsertTransaction(ctx context.Context, from, to int64, amount int64) error
+	InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error)
+	InsertTransactions(ctx context.Context, from int64, lines []model.TransferLine, event model.AuditEvent) ([]model.Transaction, error)
 }
 
 type TransferService struct {
-	repo TransferRepo
+	repo          TransferRepo
+	maxBatchLines int
 }
 
-func NewTransferService(r TransferRepo) *TransferService {
+func NewTransferService(r TransferRepo, config *config.Config) *TransferService {
 	return &TransferService{
-		repo: r,
+		repo:          r,
+		maxBatchLines: config.Transfer.MaxBatchLines,
 	}
 }
 
 func (s *TransferService) ListTransactions(ctx context.Context, req model.ListTransactionsInput) (*model.ListTransactionsOutput, error) {
 
 	if err := utils.ValidateUserID(req.UserId); err != nil {
-		return nil, err
+		return nil, InvalidArgument("INVALID_USER_ID", err)
 	}
 
 	txs, err := s.repo.ListTransactions(ctx, req.UserId)
@@ -50,30 +55,78 @@ func (s *TransferService) ListTransactions(ctx context.Context, req model.ListTr
 
 func (s *TransferService) InsertTransaction(ctx context.Context, req model.SendMoneyInput) (*model.SendMoneyOutput, error) {
 	if err := utils.ValidateUserID(req.From); err != nil {
-		return &model.SendMoneyOutput{Success: false, ErrorMessage: err.Error()}, err
+		return nil, InvalidArgument("INVALID_USER_ID", err)
 	}
 	if err := utils.ValidateUserID(req.To); err != nil {
-		return &model.SendMoneyOutput{Success: false, ErrorMessage: err.Error()}, err
+		return nil, InvalidArgument("INVALID_USER_ID", err)
 	}
 	if err := utils.ValidateAmount(req.Amount); err != nil {
-		return &model.SendMoneyOutput{Success: false, ErrorMessage: err.Error()}, err
+		return nil, InvalidArgument("INVALID_AMOUNT", err)
 	}
 	if req.From == req.To {
-		return &model.SendMoneyOutput{Success: false, ErrorMessage: "from_user cannot equal to to_user"}, fmt.Errorf("cannot transfer to yourself")
+		return nil, ErrSelfTransfer
 	}
 
-	err := s.repo.InsertTransaction(ctx, req.From, req.To, req.Amount)
+	event, err := newAuditEvent(ctx, req.From, model.AuditTransfer, req.To, "", map[string]interface{}{
+		"amount": req.Amount,
+	})
 	if err != nil {
-		return &model.SendMoneyOutput{Success: false, ErrorMessage: err.Error()}, err
+		return nil, err
+	}
+	tx, err := s.repo.InsertTransaction(ctx, req.From, req.To, req.Amount, event)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SendMoneyOutput{Success: true, Transaction: tx}, nil
+}
+
+// SendMoneyBatch pays every line from req.From in one database transaction:
+// either all lines commit or none do. Errors caused by one line carry its
+// index in the "line" metadata.
+func (s *TransferService) SendMoneyBatch(ctx context.Context, req model.SendMoneyBatchInput) (*model.SendMoneyBatchOutput, error) {
+	if err := utils.ValidateUserID(req.From); err != nil {
+		return nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	if len(req.Lines) == 0 {
+		return nil, InvalidArgument("EMPTY_BATCH", errors.New("a batch needs at least one line"))
+	}
+	if len(req.Lines) > s.maxBatchLines {
+		return nil, InvalidArgument("BATCH_TOO_LARGE", fmt.Errorf("a batch may have at most %d lines", s.maxBatchLines))
+	}
+	for i, l := range req.Lines {
+		if err := utils.ValidateUserID(l.To); err != nil {
+			return nil, LineError(i, InvalidArgument("INVALID_USER_ID", err))
+		}
+		if err := utils.ValidateAmount(l.Amount); err != nil {
+			return nil, LineError(i, InvalidArgument("INVALID_AMOUNT", err))
+		}
+		if l.To == req.From {
+			return nil, LineError(i, ErrSelfTransfer)
+		}
 	}
 
-	return &model.SendMoneyOutput{Success: true}, nil
+	var total int64
+	for _, l := range req.Lines {
+		total += l.Amount
+	}
+	event, err := newAuditEvent(ctx, req.From, model.AuditTransferBatch, 0, "", map[string]interface{}{
+		"lines": len(req.Lines),
+		"total": total,
+	})
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.repo.InsertTransactions(ctx, req.From, req.Lines, event)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SendMoneyBatchOutput{BatchID: model.BatchID(txs), Transactions: txs, Total: total}, nil
 }
 
 func (s *TransferService) GetBalance(ctx context.Context, req model.GetBalanceInput) (*model.GetBalanceOutput, error) {
 
 	if err := utils.ValidateUserID(req.UserId); err != nil {
-		return nil, err
+		return nil, InvalidArgument("INVALID_USER_ID", err)
 	}
 
 	balance, err := s.repo.GetBalance(ctx, req.UserId)
@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTransferStore(t *testing.T) *repo.MemoryTransferRepo {
+	return repotest.NewTransferRepo(t,
+		model.User{ID: 1, Name: "alice", Balance: 100, Status: model.StatusActive},
+		model.User{ID: 2, Name: "bob", Balance: 50, Status: model.StatusActive},
+		model.User{ID: 3, Name: "carol", Balance: 50, Status: model.StatusFrozen},
+		model.User{ID: 4, Name: "dave", Balance: 50, Status: model.StatusReceiveOnly},
+		model.User{ID: 5, Name: "erin", Status: model.StatusClosed},
+	)
+}
+
+func TestTransferService_InsertTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      model.SendMoneyInput
+		wantErr error
+		from    int64
+		to      int64
+	}{
+		{name: "ok", in: model.SendMoneyInput{From: 1, To: 2, Amount: 30}, from: 70, to: 80},
+		{name: "whole balance", in: model.SendMoneyInput{From: 2, To: 1, Amount: 50}, from: 0, to: 150},
+		{name: "to receive-only", in: model.SendMoneyInput{From: 1, To: 4, Amount: 10}, from: 90, to: 60},
+		{name: "invalid sender", in: model.SendMoneyInput{From: 0, To: 2, Amount: 1}, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "invalid recipient", in: model.SendMoneyInput{From: 1, To: -2, Amount: 1}, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "zero amount", in: model.SendMoneyInput{From: 1, To: 2}, wantErr: &service.Error{Reason: "INVALID_AMOUNT"}},
+		{name: "negative amount", in: model.SendMoneyInput{From: 1, To: 2, Amount: -5}, wantErr: &service.Error{Reason: "INVALID_AMOUNT"}},
+		{name: "self transfer", in: model.SendMoneyInput{From: 1, To: 1, Amount: 1}, wantErr: service.ErrSelfTransfer},
+		{name: "insufficient funds", in: model.SendMoneyInput{From: 2, To: 1, Amount: 51}, wantErr: service.ErrInsufficientFunds},
+		{name: "unknown recipient", in: model.SendMoneyInput{From: 1, To: 99, Amount: 1}, wantErr: service.ErrUserNotFound},
+		{name: "frozen sender", in: model.SendMoneyInput{From: 3, To: 1, Amount: 1}, wantErr: service.ErrSenderFrozen},
+		{name: "receive-only sender", in: model.SendMoneyInput{From: 4, To: 1, Amount: 1}, wantErr: service.ErrSenderReceiveOnly},
+		{name: "frozen recipient", in: model.SendMoneyInput{From: 1, To: 3, Amount: 1}, wantErr: service.ErrRecipientFrozen},
+		{name: "closed recipient", in: model.SendMoneyInput{From: 1, To: 5, Amount: 1}, wantErr: service.ErrRecipientClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTransferStore(t)
+			svc := service.NewTransferService(store, config.LoadConfig())
+
+			out, err := svc.InsertTransaction(context.Background(), tt.in)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Empty(t, transactions(t, store))
+				require.Empty(t, auditEvents(t, store))
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, out.Success)
+			require.Equal(t, tt.in.Amount, out.Transaction.Amount)
+			require.Equal(t, tt.from, balanceOf(t, store, tt.in.From))
+			require.Equal(t, tt.to, balanceOf(t, store, tt.in.To))
+
+			events := auditEvents(t, store)
+			require.Len(t, events, 1)
+			require.Equal(t, model.AuditTransfer, events[0].Action)
+			require.Equal(t, tt.in.From, events[0].ActorID)
+			require.Equal(t, tt.in.To, events[0].TargetUserID)
+			require.Contains(t, events[0].Details, fmt.Sprintf(`"transaction_id":%d`, out.Transaction.ID))
+		})
+	}
+}
+
+func TestTransferService_SendMoneyBatch(t *testing.T) {
+	lines := func(pairs ...int64) []model.TransferLine {
+		var out []model.TransferLine
+		for i := 0; i+1 < len(pairs); i += 2 {
+			out = append(out, model.TransferLine{To: pairs[i], Amount: pairs[i+1]})
+		}
+		return out
+	}
+	tests := []struct {
+		name     string
+		in       model.SendMoneyBatchInput
+		wantErr  error
+		wantLine string
+		balances map[int64]int64
+	}{
+		{name: "ok", in: model.SendMoneyBatchInput{From: 1, Lines: lines(2, 30, 4, 20, 2, 5)},
+			balances: map[int64]int64{1: 45, 2: 85, 4: 70}},
+		{name: "whole balance", in: model.SendMoneyBatchInput{From: 2, Lines: lines(1, 25, 4, 25)},
+			balances: map[int64]int64{1: 125, 2: 0, 4: 75}},
+		{name: "empty", in: model.SendMoneyBatchInput{From: 1}, wantErr: &service.Error{Reason: "EMPTY_BATCH"}},
+		{name: "too large", in: model.SendMoneyBatchInput{From: 1, Lines: make([]model.TransferLine, 4)}, wantErr: &service.Error{Reason: "BATCH_TOO_LARGE"}},
+		{name: "invalid sender", in: model.SendMoneyBatchInput{Lines: lines(2, 1)}, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "invalid amount", in: model.SendMoneyBatchInput{From: 1, Lines: lines(2, 1, 4, 0)}, wantErr: &service.Error{Reason: "INVALID_AMOUNT"}, wantLine: "1"},
+		{name: "self transfer", in: model.SendMoneyBatchInput{From: 1, Lines: lines(1, 1)}, wantErr: service.ErrSelfTransfer, wantLine: "0"},
+		{name: "insufficient total", in: model.SendMoneyBatchInput{From: 2, Lines: lines(1, 30, 4, 21)}, wantErr: service.ErrInsufficientFunds},
+		{name: "frozen recipient", in: model.SendMoneyBatchInput{From: 1, Lines: lines(2, 1, 4, 1, 3, 1)}, wantErr: service.ErrRecipientFrozen, wantLine: "2"},
+		{name: "unknown recipient", in: model.SendMoneyBatchInput{From: 1, Lines: lines(99, 1)}, wantErr: service.ErrUserNotFound, wantLine: "0"},
+		{name: "frozen sender", in: model.SendMoneyBatchInput{From: 3, Lines: lines(1, 1)}, wantErr: service.ErrSenderFrozen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTransferStore(t)
+			cfg := config.LoadConfig()
+			cfg.Transfer.MaxBatchLines = 3
+			svc := service.NewTransferService(store, cfg)
+
+			out, err := svc.SendMoneyBatch(context.Background(), tt.in)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				e, ok := service.AsError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.wantLine, e.Metadata["line"])
+				require.Empty(t, transactions(t, store))
+				require.Empty(t, auditEvents(t, store))
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, out.Transactions, len(tt.in.Lines))
+			for i, tx := range out.Transactions {
+				require.Equal(t, tt.in.Lines[i].To, tx.To)
+				require.Equal(t, tt.in.Lines[i].Amount, tx.Amount)
+			}
+			require.Equal(t, model.BatchID(out.Transactions), out.BatchID)
+			for id, want := range tt.balances {
+				require.Equal(t, want, balanceOf(t, store, id), "balance of user %d", id)
+			}
+			events := auditEvents(t, store)
+			require.Len(t, events, 1)
+			require.Equal(t, model.AuditTransferBatch, events[0].Action)
+			require.Contains(t, events[0].Details, out.BatchID)
+		})
+	}
+}
+
+func TestTransferService_GetBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		want    int64
+		wantErr error
+	}{
+		{name: "ok", userID: 1, want: 100},
+		{name: "frozen accounts can read", userID: 3, want: 50},
+		{name: "invalid id", userID: 0, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+		{name: "unknown user", userID: 99, wantErr: service.ErrUserNotFound},
+	}
+	svc := service.NewTransferService(newTransferStore(t), config.LoadConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.GetBalance(context.Background(), model.GetBalanceInput{UserId: tt.userID})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.userID, out.UserId)
+			require.Equal(t, tt.want, out.Balance)
+		})
+	}
+}
+
+func TestTransferService_ListTransactions(t *testing.T) {
+	store := newTransferStore(t)
+	svc := service.NewTransferService(store, config.LoadConfig())
+	ctx := context.Background()
+	for _, in := range []model.SendMoneyInput{
+		{From: 1, To: 2, Amount: 10},
+		{From: 2, To: 1, Amount: 5},
+		{From: 1, To: 4, Amount: 7},
+	} {
+		_, err := svc.InsertTransaction(ctx, in)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  int64
+		amounts []int64
+		wantErr error
+	}{
+		{name: "sent only", userID: 1, amounts: []int64{10, 7}},
+		{name: "single", userID: 2, amounts: []int64{5}},
+		{name: "none", userID: 4},
+		{name: "invalid id", userID: -1, wantErr: &service.Error{Reason: "INVALID_USER_ID"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.ListTransactions(ctx, model.ListTransactionsInput{UserId: tt.userID})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, len(tt.amounts), out.Number)
+			var amounts []int64
+			for _, tx := range out.Transactions {
+				require.Equal(t, tt.userID, tx.From)
+				amounts = append(amounts, tx.Amount)
+			}
+			require.Equal(t, tt.amounts, amounts)
+		})
+	}
+}
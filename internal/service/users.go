@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"project/internal/model"
+	"project/internal/utils"
+)
+
+const (
+	minPasswordLength = 8
+	maxSeedUsers      = 10000
+)
+
+// UserRepo is what the users CLI needs from storage.
+type UserRepo interface {
+	CreateUser(ctx context.Context, user *model.User, balance int64, event model.AuditEvent) error
+	SetPassword(ctx context.Context, userID int64, hash string, event model.AuditEvent) error
+	GetUser(ctx context.Context, userID int64) (model.User, error)
+	SearchUsers(ctx context.Context, query string, afterID int64, limit int) ([]model.User, error)
+	ListUserTransactions(ctx context.Context, userID int64, beforeID uint, limit int) ([]model.Transaction, error)
+	AdjustBalance(ctx context.Context, userID, amount int64, event model.AuditEvent) (model.Transaction, int64, error)
+	InsertTransaction(ctx context.Context, from, to int64, amount int64, event model.AuditEvent) (model.Transaction, error)
+	RecordAuditEvent(ctx context.Context, event model.AuditEvent) error
+	SetHotBuckets(ctx context.Context, userID int64, buckets int, event model.AuditEvent) error
+}
+
+// UserService backs the offline `users` commands. Unlike AdminService it
+// has no caller identity to check: whoever can reach the database is
+// trusted, and events are recorded against the system account.
+type UserService struct {
+	repo UserRepo
+	hash func(password string) (string, error)
+}
+
+func NewUserService(repo UserRepo) *UserService {
+	return &UserService{repo: repo, hash: utils.HashPassword}
+}
+
+func (s *UserService) CreateUser(ctx context.Context, in model.CreateUserInput) (model.User, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return model.User{}, InvalidArgument("INVALID_NAME", errors.New("name is required"))
+	}
+	if err := validatePassword(in.Password); err != nil {
+		return model.User{}, err
+	}
+	roles, err := normalizeRoles(in.Roles)
+	if err != nil {
+		return model.User{}, err
+	}
+	if in.Balance < 0 {
+		return model.User{}, InvalidArgument("INVALID_AMOUNT", errors.New("opening balance must not be negative"))
+	}
+	hash, err := s.hash(in.Password)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user := model.User{Name: name, Password: hash, Roles: roles, Status: model.StatusActive}
+	if err := s.createUser(ctx, in.ActorID, &user, in.Balance, nil); err != nil {
+		return model.User{}, err
+	}
+	log.Printf("[Users] created user=%d name=%q roles=%q balance=%d", user.ID, user.Name, user.Roles, user.Balance)
+	return user, nil
+}
+
+func (s *UserService) createUser(ctx context.Context, actorID int64, user *model.User, balance int64, details map[string]interface{}) error {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["name"] = user.Name
+	details["roles"] = user.Roles
+	details["balance"] = balance
+	event, err := newAuditEvent(ctx, actorID, model.AuditCreateUser, 0, "", details)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateUser(ctx, user, balance, event)
+}
+
+func (s *UserService) SetPassword(ctx context.Context, in model.SetPasswordInput) error {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return InvalidArgument("INVALID_USER_ID", err)
+	}
+	if err := validatePassword(in.Password); err != nil {
+		return err
+	}
+	hash, err := s.hash(in.Password)
+	if err != nil {
+		return err
+	}
+	event, err := newAuditEvent(ctx, in.ActorID, model.AuditSetPassword, in.UserID, "", nil)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetPassword(ctx, in.UserID, hash, event); err != nil {
+		return err
+	}
+	log.Printf("[Users] password changed for user=%d", in.UserID)
+	return nil
+}
+
+// SetHotBuckets switches hot-account mode: with Buckets > 0, credits to the
+// user land in that many buckets and are swept into the balance in the
+// background; 0 switches it off and sweeps at once.
+func (s *UserService) SetHotBuckets(ctx context.Context, in model.SetHotBucketsInput) error {
+	if err := utils.ValidateUserID(in.UserID); err != nil {
+		return InvalidArgument("INVALID_USER_ID", err)
+	}
+	if in.Buckets < 0 || in.Buckets > model.MaxHotBuckets {
+		return InvalidArgument("INVALID_BUCKETS", fmt.Errorf("buckets must be between 0 and %d", model.MaxHotBuckets))
+	}
+	event, err := newAuditEvent(ctx, in.ActorID, model.AuditSetHotBuckets, in.UserID, "", map[string]interface{}{"buckets": in.Buckets})
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetHotBuckets(ctx, in.UserID, in.Buckets, event); err != nil {
+		return err
+	}
+	log.Printf("[Users] hot buckets for user=%d set to %d", in.UserID, in.Buckets)
+	return nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, query string, limit int) ([]model.User, error) {
+	size, err := pageSize(limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.SearchUsers(ctx, strings.TrimSpace(query), 0, size)
+}
+
+// GetUser returns the user and up to limit of their most recent transactions.
+func (s *UserService) GetUser(ctx context.Context, userID int64, limit int) (model.User, []model.Transaction, error) {
+	if err := utils.ValidateUserID(userID); err != nil {
+		return model.User{}, nil, InvalidArgument("INVALID_USER_ID", err)
+	}
+	size, err := pageSize(limit)
+	if err != nil {
+		return model.User{}, nil, err
+	}
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return model.User{}, nil, err
+	}
+	txs, err := s.repo.ListUserTransactions(ctx, userID, 0, size)
+	if err != nil {
+		return model.User{}, nil, err
+	}
+	return user, txs, nil
+}
+
+// Credit adds (or, with a negative amount, removes) funds exactly as the
+// admin AdjustBalance RPC does.
+func (s *UserService) Credit(ctx context.Context, in model.AdjustBalanceInput) (*model.AdjustBalanceOutput, error) {
+	return adjustBalance(ctx, s.repo, in)
+}
+
+var (
+	seedFirstNames = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy", "mallory", "niaj", "olivia", "peggy", "rupert", "sybil", "trent", "victor", "walter", "yasmin"}
+	seedLastNames  = []string{"adams", "baker", "chen", "diaz", "evans", "fischer", "garcia", "haddad", "ito", "jones", "kim", "lopez", "meyer", "nguyen", "okafor", "patel", "rossi", "silva", "tanaka", "weber"}
+)
+
+// Seed creates Count users with the same password and opening balance, then
+// makes Transactions random transfers between them. Transfers the sender
+// cannot afford are skipped, so the result may hold fewer than requested.
+func (s *UserService) Seed(ctx context.Context, in model.SeedUsersInput) (*model.SeedUsersOutput, error) {
+	if in.Count <= 0 || in.Count > maxSeedUsers {
+		return nil, InvalidArgument("INVALID_COUNT", fmt.Errorf("count must be between 1 and %d", maxSeedUsers))
+	}
+	if in.Balance < 0 {
+		return nil, InvalidArgument("INVALID_AMOUNT", errors.New("opening balance must not be negative"))
+	}
+	if in.Transactions < 0 {
+		return nil, InvalidArgument("INVALID_COUNT", errors.New("transactions must not be negative"))
+	}
+	if err := validatePassword(in.Password); err != nil {
+		return nil, err
+	}
+	// bcrypt is deliberately slow; one hash is shared by every seeded user.
+	hash, err := s.hash(in.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(in.Seed))
+	out := &model.SeedUsersOutput{}
+	for i := 0; i < in.Count; i++ {
+		user := model.User{
+			Name:     seedFirstNames[rng.Intn(len(seedFirstNames))] + "." + seedLastNames[rng.Intn(len(seedLastNames))],
+			Password: hash,
+			Roles:    model.RoleUser,
+			Status:   model.StatusActive,
+		}
+		if err := s.createUser(ctx, in.ActorID, &user, in.Balance, map[string]interface{}{"seed": in.Seed}); err != nil {
+			return out, fmt.Errorf("create user %d of %d: %w", i+1, in.Count, err)
+		}
+		out.Users = append(out.Users, user)
+	}
+
+	if len(out.Users) > 1 {
+		maxAmount := in.Balance / 10
+		if maxAmount < 1 {
+			maxAmount = 1
+		}
+		for i := 0; i < in.Transactions; i++ {
+			from := rng.Intn(len(out.Users))
+			to := rng.Intn(len(out.Users) - 1)
+			if to >= from {
+				to++
+			}
+			amount := 1 + rng.Int63n(maxAmount)
+			event, err := newAuditEvent(ctx, in.ActorID, model.AuditTransfer, out.Users[to].ID, "", map[string]interface{}{
+				"from":   out.Users[from].ID,
+				"amount": amount,
+				"seed":   in.Seed,
+			})
+			if err != nil {
+				return out, err
+			}
+			_, err = s.repo.InsertTransaction(ctx, out.Users[from].ID, out.Users[to].ID, amount, event)
+			if errors.Is(err, ErrInsufficientFunds) {
+				continue
+			}
+			if err != nil {
+				return out, fmt.Errorf("seed transfer %d of %d: %w", i+1, in.Transactions, err)
+			}
+			out.Transactions++
+		}
+	}
+
+	event, err := newAuditEvent(ctx, in.ActorID, model.AuditSeedUsers, 0, "", map[string]interface{}{
+		"count":        len(out.Users),
+		"balance":      in.Balance,
+		"transactions": out.Transactions,
+		"seed":         in.Seed,
+	})
+	if err != nil {
+		return out, err
+	}
+	if err := s.repo.RecordAuditEvent(ctx, event); err != nil {
+		return out, err
+	}
+	log.Printf("[Users] seeded %d user(s) and %d transaction(s) (seed=%d)", len(out.Users), out.Transactions, in.Seed)
+	return out, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return InvalidArgument("INVALID_PASSWORD", fmt.Errorf("password must be at least %d characters", minPasswordLength))
+	}
+	return nil
+}
+
+// normalizeRoles deduplicates roles, rejects unknown ones and always
+// includes the base user role.
+func normalizeRoles(roles []string) (string, error) {
+	out := []string{model.RoleUser}
+	for _, r := range roles {
+		r = strings.TrimSpace(r)
+		if r == "" || slices.Contains(out, r) {
+			continue
+		}
+		if !slices.Contains(model.KnownRoles, r) {
+			return "", InvalidArgument("INVALID_ROLE", fmt.Errorf("unknown role %q", r))
+		}
+		out = append(out, r)
+	}
+	return strings.Join(out, " "), nil
+}
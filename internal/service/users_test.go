@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/repo/repotest"
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestUserService(t *testing.T) (*service.UserService, *repo.MemoryTransferRepo) {
+	store := repotest.NewTransferRepo(t)
+	return service.NewUserService(store), store
+}
+
+func requirePassword(t *testing.T, store *repo.MemoryTransferRepo, userID int64, password string) {
+	t.Helper()
+	hash, err := store.GetPassword(context.Background(), userID)
+	require.NoError(t, err)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)))
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	svc, store := newTestUserService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateUser(ctx, model.CreateUserInput{Name: "carol", Password: "short"})
+	require.ErrorIs(t, err, &service.Error{Reason: "INVALID_PASSWORD"})
+	_, err = svc.CreateUser(ctx, model.CreateUserInput{Name: "carol", Password: "long enough", Roles: []string{"root"}})
+	require.ErrorIs(t, err, &service.Error{Reason: "INVALID_ROLE"})
+	require.Empty(t, auditEvents(t, store))
+
+	u, err := svc.CreateUser(ctx, model.CreateUserInput{
+		Name: " carol ", Password: "long enough", Roles: []string{"admin", "user", "admin"}, Balance: 500,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "carol", u.Name)
+	require.Equal(t, "user admin", u.Roles)
+	requirePassword(t, store, u.ID, "long enough")
+	require.EqualValues(t, 500, balanceOf(t, store, u.ID))
+	require.EqualValues(t, -500, balanceOf(t, store, model.SystemAccountID))
+	events := auditEvents(t, store)
+	require.Len(t, events, 1)
+	require.Equal(t, model.AuditCreateUser, events[0].Action)
+	require.NotContains(t, events[0].Details, "long enough")
+
+	require.NoError(t, svc.SetPassword(ctx, model.SetPasswordInput{UserID: u.ID, Password: "another one"}))
+	requirePassword(t, store, u.ID, "another one")
+	require.Equal(t, model.AuditSetPassword, auditEvents(t, store)[1].Action)
+}
+
+func TestUserService_SeedIsRepeatableAndConservesMoney(t *testing.T) {
+	type transfer struct{ from, to, amount int64 }
+	run := func() (*repo.MemoryTransferRepo, *model.SeedUsersOutput, []transfer) {
+		svc, store := newTestUserService(t)
+		out, err := svc.Seed(context.Background(), model.SeedUsersInput{
+			Count: 20, Balance: 1000, Transactions: 200, Password: "password123", Seed: 42,
+		})
+		require.NoError(t, err)
+		var transfers []transfer
+		for _, tx := range transactions(t, store) {
+			transfers = append(transfers, transfer{tx.From, tx.To, tx.Amount})
+		}
+		return store, out, transfers
+	}
+	store, out, transfers := run()
+	require.Len(t, out.Users, 20)
+	require.NotZero(t, out.Transactions)
+
+	users, err := store.SearchUsers(context.Background(), "", -1, 100)
+	require.NoError(t, err)
+	var total int64
+	for _, u := range users {
+		total += u.Balance
+	}
+	require.Zero(t, total)
+
+	_, outAgain, transfersAgain := run()
+	require.Equal(t, out.Transactions, outAgain.Transactions)
+	require.Equal(t, transfers, transfersAgain)
+	events := auditEvents(t, store)
+	require.Equal(t, model.AuditSeedUsers, events[len(events)-1].Action)
+}
+
+func TestUserService_SetHotBuckets(t *testing.T) {
+	svc, store := newTestUserService(t)
+	ctx := context.Background()
+	u, err := svc.CreateUser(ctx, model.CreateUserInput{Name: "shop", Password: "long enough"})
+	require.NoError(t, err)
+
+	for _, buckets := range []int{-1, model.MaxHotBuckets + 1} {
+		err := svc.SetHotBuckets(ctx, model.SetHotBucketsInput{UserID: u.ID, Buckets: buckets})
+		require.ErrorIs(t, err, &service.Error{Reason: "INVALID_BUCKETS"})
+	}
+	err = svc.SetHotBuckets(ctx, model.SetHotBucketsInput{UserID: 99, Buckets: 8})
+	require.ErrorIs(t, err, &service.Error{Reason: "USER_NOT_FOUND"})
+
+	require.NoError(t, svc.SetHotBuckets(ctx, model.SetHotBucketsInput{UserID: u.ID, Buckets: 8}))
+	require.Equal(t, 8, userOf(t, store, u.ID).HotBuckets)
+	events := auditEvents(t, store)
+	last := events[len(events)-1]
+	require.Equal(t, model.AuditSetHotBuckets, last.Action)
+	require.Equal(t, u.ID, last.TargetUserID)
+	require.JSONEq(t, `{"buckets":8}`, last.Details)
+}
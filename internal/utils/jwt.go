@@ -1,33 +1,142 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type Claims struct {
-	UserID int64 `json:"user_id"`
+	UserID int64    `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID int64, accessTokenTTL time.Duration, accessSecret string) (string, error) {
+// KeySet signs access tokens with one private key and verifies them against
+// every configured public key, looked up by the token's kid header. Keeping
+// the previous key in the verification set lets tokens it signed stay valid
+// while the signing key is rotated.
+type KeySet struct {
+	issuer   string
+	audience string
+
+	signer     crypto.Signer
+	signingKID string
+
+	keys map[string]verificationKey
+}
+
+type verificationKey struct {
+	alg string
+	pub crypto.PublicKey
+}
+
+// NewKeySet signs with signingKey, an RSA (RS256) or Ed25519 (EdDSA) private
+// key, and also accepts tokens signed by any of verificationKeys. The kid of
+// each key is its RFC 7638 thumbprint, so it needs no configuration.
+func NewKeySet(issuer, audience string, signingKey crypto.Signer, verificationKeys ...crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{
+		issuer:   issuer,
+		audience: audience,
+		signer:   signingKey,
+		keys:     make(map[string]verificationKey),
+	}
+	kid, err := ks.add(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	ks.signingKID = kid
+	for _, pub := range verificationKeys {
+		if _, err := ks.add(pub); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func (ks *KeySet) add(pub crypto.PublicKey) (string, error) {
+	alg, err := algorithmFor(pub)
+	if err != nil {
+		return "", err
+	}
+	kid, err := thumbprint(pub)
+	if err != nil {
+		return "", err
+	}
+	ks.keys[kid] = verificationKey{alg: alg, pub: pub}
+	return kid, nil
+}
+
+func algorithmFor(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return "", fmt.Errorf("jwt: RSA key must be at least 2048 bits, got %d", k.N.BitLen())
+		}
+		return jwt.SigningMethodRS256.Alg(), nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	default:
+		return "", fmt.Errorf("jwt: unsupported key type %T", pub)
+	}
+}
+
+func (ks *KeySet) GenerateAccessToken(userID int64, roles []string, accessTokenTTL time.Duration) (string, error) {
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
+		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    ks.issuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			Audience:  jwt.ClaimStrings{ks.audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(accessSecret))
+	method := jwt.GetSigningMethod(ks.keys[ks.signingKID].alg)
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signer)
 }
 
-func ValidateAccessToken(tokenStr string, accessSecret string) (*Claims, error) {
+// ValidateAccessToken accepts only tokens whose kid is known, whose alg
+// matches that key, and whose iss, aud, exp and nbf claims are present and
+// valid.
+func (ks *KeySet) ValidateAccessToken(tokenStr string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(accessSecret), nil
-	})
+		kid, _ := t.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if t.Method.Alg() != key.alg {
+			return nil, fmt.Errorf("algorithm %s does not match key %s", t.Method.Alg(), kid)
+		}
+		return key.pub, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(ks.issuer),
+		jwt.WithAudience(ks.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid access token")
 	}
@@ -35,5 +144,115 @@ func ValidateAccessToken(tokenStr string, accessSecret string) (*Claims, error)
 	if !ok {
 		return nil, errors.New("invalid claims")
 	}
+	if claims.NotBefore == nil {
+		return nil, errors.New("invalid access token: missing nbf")
+	}
 	return claims, nil
 }
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS returns the public verification keys as a JSON Web Key Set.
+func (ks *KeySet) JWKS() ([]byte, error) {
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: []jwk{}}
+	for kid, key := range ks.keys {
+		k := jwk{Kid: kid, Use: "sig", Alg: key.alg}
+		switch pub := key.pub.(type) {
+		case *rsa.PublicKey:
+			k.Kty, k.N, k.E = "RSA", b64(pub.N.Bytes()), b64(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			k.Kty, k.Crv, k.X = "OKP", "Ed25519", b64(pub)
+		}
+		set.Keys = append(set.Keys, k)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return json.Marshal(set)
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint: the SHA-256 of the
+// required members in lexicographic order with no whitespace.
+func thumbprint(pub crypto.PublicKey) (string, error) {
+	var canonical string
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, b64(big.NewInt(int64(k.E)).Bytes()), b64(k.N.Bytes()))
+	case ed25519.PublicKey:
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, b64(k))
+	default:
+		return "", fmt.Errorf("jwt: unsupported key type %T", pub)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return b64(sum[:]), nil
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// LoadSigningKey reads a PEM private key (PKCS#8, or PKCS#1 for RSA).
+func LoadSigningKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("jwt: %s: unsupported private key type %T", path, key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("jwt: %s: not a PKCS#8 or PKCS#1 private key", path)
+}
+
+// LoadVerificationKey reads a PEM public key, or the public half of a PEM
+// private key.
+func LoadVerificationKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	signer, err := LoadSigningKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Public(), nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: %s: no PEM data", path)
+	}
+	return block, nil
+}
+
+// GenerateEd25519Key returns a fresh signing key. Tokens it signs stop
+// validating when the process exits.
+func GenerateEd25519Key() (crypto.Signer, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeySet(t *testing.T, verification ...*KeySet) *KeySet {
+	t.Helper()
+	signer, err := GenerateEd25519Key()
+	require.NoError(t, err)
+	ks, err := NewKeySet("transfer-service", "transfer-api", signer)
+	require.NoError(t, err)
+	for _, v := range verification {
+		_, err := ks.add(v.signer.Public())
+		require.NoError(t, err)
+	}
+	return ks
+}
+
+func TestKeySet_RoundTripAndRotation(t *testing.T) {
+	old := newTestKeySet(t)
+	token, err := old.GenerateAccessToken(42, []string{"user"}, time.Minute)
+	require.NoError(t, err)
+
+	claims, err := old.ValidateAccessToken(token)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), claims.UserID)
+	require.Equal(t, []string{"user"}, claims.Roles)
+
+	// After rotation the new key signs, and the old key's tokens still verify.
+	rotated := newTestKeySet(t, old)
+	_, err = rotated.ValidateAccessToken(token)
+	require.NoError(t, err)
+
+	// A key set that never knew the old key rejects its tokens.
+	_, err = newTestKeySet(t).ValidateAccessToken(token)
+	require.Error(t, err)
+
+	var jwks struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	doc, err := rotated.JWKS()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(doc, &jwks))
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "EdDSA", jwks.Keys[0]["alg"])
+}
+
+func TestKeySet_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ks, err := NewKeySet("transfer-service", "transfer-api", key)
+	require.NoError(t, err)
+
+	token, err := ks.GenerateAccessToken(7, nil, time.Minute)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	require.Equal(t, "RS256", parsed.Method.Alg())
+	require.Equal(t, ks.signingKID, parsed.Header["kid"])
+
+	_, err = ks.ValidateAccessToken(token)
+	require.NoError(t, err)
+}
+
+func TestKeySet_RejectsBadTokens(t *testing.T) {
+	ks := newTestKeySet(t)
+	now := time.Now()
+	valid := Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "transfer-service",
+			Audience:  jwt.ClaimStrings{"transfer-api"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	sign := func(c Claims) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, c)
+		tok.Header["kid"] = ks.signingKID
+		s, err := tok.SignedString(ks.signer)
+		require.NoError(t, err)
+		return s
+	}
+
+	_, err := ks.ValidateAccessToken(sign(valid))
+	require.NoError(t, err)
+
+	wrongIss := valid
+	wrongIss.Issuer = "someone-else"
+	wrongAud := valid
+	wrongAud.Audience = jwt.ClaimStrings{"other-api"}
+	noNbf := valid
+	noNbf.NotBefore = nil
+	future := valid
+	future.NotBefore = jwt.NewNumericDate(now.Add(time.Hour))
+
+	for name, c := range map[string]Claims{"issuer": wrongIss, "audience": wrongAud, "missing nbf": noNbf, "not yet valid": future} {
+		_, err := ks.ValidateAccessToken(sign(c))
+		require.Error(t, err, name)
+	}
+
+	// HS256 signed with the public key bytes must not be accepted.
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, valid)
+	hs.Header["kid"] = ks.signingKID
+	forged, err := hs.SignedString([]byte(ks.keys[ks.signingKID].pub.(ed25519.PublicKey)))
+	require.NoError(t, err)
+	_, err = ks.ValidateAccessToken(forged)
+	require.Error(t, err)
+}
@@ -9,6 +9,13 @@ func main() {
 	cmd.RegisterCommands(
 		cmd.NewServeCommand(),
 		cmd.NewPubSubConsumerCommand(),
+		cmd.NewDLQCommand(),
+		cmd.NewEventsCommand(),
+		cmd.NewAuditCommand(),
+		cmd.NewMigrateCommand(),
+		cmd.NewUsersCommand(),
+		cmd.NewClientCommand(),
+		cmd.NewDevCommand(),
 	)
 
 	cmd.Execute()
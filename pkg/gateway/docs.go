@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// openapiSpec is generated from pkg/probuf/transfer.proto by
+// protoc-gen-openapiv2; see the README for the command.
+//
+//go:embed openapi/transfer.swagger.json
+var openapiSpec []byte
+
+// The docs page loads Swagger UI from a CDN and points it at /openapi.json.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Transfer API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// RegisterDocs serves the embedded OpenAPI document at /openapi.json and an
+// interactive docs page at /docs.
+func RegisterDocs(mux *runtime.ServeMux) error {
+	if err := mux.HandlePath(http.MethodGet, "/openapi.json", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openapiSpec)
+	}); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, "/docs", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(docsPage))
+	})
+}
+
+// RegisterJWKS publishes the public JWT verification keys at
+// /.well-known/jwks.json so other services can verify access tokens.
+func RegisterJWKS(mux *runtime.ServeMux, jwks []byte) error {
+	return mux.HandlePath(http.MethodGet, "/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_, _ = w.Write(jwks)
+	})
+}
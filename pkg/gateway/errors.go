@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorBody is the JSON shape of every error the gateway returns. Code is the
+// gRPC code name (e.g. FAILED_PRECONDITION); Reason is the ErrorInfo reason
+// when the server attached one.
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Reason    string `json:"reason,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrorHandler replaces runtime.DefaultHTTPErrorHandler. Routing errors go
+// through it as well, so 404/405 responses share the same shape.
+func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for k, vs := range md.HeaderMD {
+			for _, v := range vs {
+				w.Header().Add(runtime.MetadataHeaderPrefix+k, v)
+			}
+		}
+	}
+	writeError(w, r, runtime.HTTPStatusFromCode(st.Code()), st, reasonOf(st))
+}
+
+func reasonOf(st *status.Status) string {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, httpStatus int, st *status.Status, reason string) {
+	body := ErrorBody{
+		Code:      code.Code_name[int32(st.Code())],
+		Message:   st.Message(),
+		Reason:    reason,
+		RequestID: r.Header.Get(RequestIDHeader),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(body)
+}
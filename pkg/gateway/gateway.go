@@ -0,0 +1,56 @@
+// Package gateway holds the HTTP side of the grpc-gateway: mux options, the
+// JSON error body and the middleware placed in front of the mux.
+package gateway
+
+import (
+	"net/http"
+	"net/textproto"
+
+	"project/config"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// Forwarded request headers and the gRPC metadata keys they arrive under.
+// User-Agent is renamed because gRPC reserves that key for its own client.
+// X-Forwarded-For needs no entry: the runtime always forwards it with the
+// caller's address appended.
+var forwardedHeaders = map[string]string{
+	"X-Request-Id":    "x-request-id",
+	"User-Agent":      "x-forwarded-user-agent",
+	"Idempotency-Key": "idempotency-key",
+	"X-Api-Key":       "x-api-key",
+}
+
+func NewServeMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithErrorHandler(ErrorHandler),
+		runtime.WithIncomingHeaderMatcher(HeaderMatcher),
+	)
+}
+
+func HeaderMatcher(key string) (string, bool) {
+	if md, ok := forwardedHeaders[textproto.CanonicalMIMEHeaderKey(key)]; ok {
+		return md, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// Handler wraps the mux with request IDs, CORS and body limits, in that order,
+// so even rejected requests get a request ID.
+func Handler(cfg config.GatewayConfig, mux http.Handler) http.Handler {
+	h := limitBody(cfg.MaxBodyBytes, mux)
+	h = cors(cfg.CORSAllowedOrigins, cfg.CORSAllowedHeaders, h)
+	return requestID(h)
+}
+
+func NewServer(cfg config.GatewayConfig, mux http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           Handler(cfg, mux),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
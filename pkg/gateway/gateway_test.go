@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project/config"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testConfig() config.GatewayConfig {
+	return config.GatewayConfig{
+		CORSAllowedOrigins: []string{"https://app.example.com"},
+		CORSAllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxBodyBytes:       16,
+	}
+}
+
+func TestHandler_CORSPreflight(t *testing.T) {
+	h := Handler(testConfig(), http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/transfer/send", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Authorization, Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+	require.NotEmpty(t, rec.Header().Get(RequestIDHeader))
+
+	req = httptest.NewRequest(http.MethodOptions, "/v1/transfer/send", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestHandler_RejectsLargeBody(t *testing.T) {
+	h := Handler(testConfig(), http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/transfer/send", strings.NewReader(`{"to": 2, "amount": 100000}`))
+	req.Header.Set(RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var body ErrorBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "REQUEST_TOO_LARGE", body.Reason)
+	require.Equal(t, "req-1", body.RequestID)
+}
+
+func TestErrorHandler_Body(t *testing.T) {
+	st, err := status.New(codes.FailedPrecondition, "insufficient balance").
+		WithDetails(&errdetails.ErrorInfo{Reason: "INSUFFICIENT_FUNDS", Domain: "transfer.v1"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/transfer/send", nil)
+	req.Header.Set(RequestIDHeader, "req-2")
+	rec := httptest.NewRecorder()
+	ErrorHandler(context.Background(), nil, nil, rec, req, st.Err())
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var body ErrorBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, ErrorBody{
+		Code:      "FAILED_PRECONDITION",
+		Message:   "insufficient balance",
+		Reason:    "INSUFFICIENT_FUNDS",
+		RequestID: "req-2",
+	}, body)
+}
+
+func TestHeaderMatcher(t *testing.T) {
+	for header, want := range map[string]string{
+		"x-request-id":    "x-request-id",
+		"Idempotency-Key": "idempotency-key",
+		"User-Agent":      "x-forwarded-user-agent",
+	} {
+		got, ok := HeaderMatcher(header)
+		require.True(t, ok)
+		require.Equal(t, want, got)
+	}
+	_, ok := HeaderMatcher("X-Internal-Secret")
+	require.False(t, ok)
+}
+
+func TestRegisterDocs(t *testing.T) {
+	mux := NewServeMux()
+	require.NoError(t, RegisterDocs(mux))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		Paths               map[string]json.RawMessage `json:"paths"`
+		SecurityDefinitions map[string]json.RawMessage `json:"securityDefinitions"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	require.Contains(t, spec.Paths, "/v1/transfer/balance")
+	require.Contains(t, spec.SecurityDefinitions, "bearer")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "/openapi.json")
+}
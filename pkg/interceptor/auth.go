@@ -2,11 +2,15 @@ package interceptor
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log"
 	"strings"
 
 	"project/config"
+	"project/internal/model"
+	"project/internal/service"
 	"project/internal/utils"
+	pb "project/pkg/pb"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -14,21 +18,27 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func isProtectedMethod(method string) bool {
-	switch method {
-	case
-		"/transfer.v1.AuthService/Login":
-		return false
-	default:
-		return true
-	}
-}
-
 type RedisToken interface {
 	GetToken(ctx context.Context, userID int64) (string, error)
 }
 
-func NewAuthInterceptor(redis RedisToken, config *config.Config) grpc.UnaryServerInterceptor {
+type TokenValidator interface {
+	ValidateAccessToken(tokenStr string) (*utils.Claims, error)
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*model.APIKeyPrincipal, error)
+}
+
+type AccountStatus interface {
+	GetAccountStatus(ctx context.Context, userID int64) (string, error)
+}
+
+// NewAuthInterceptor enforces the auth_policy option declared on each method
+// in transfer.proto; see LoadPolicies. Requests acting as a closed account are
+// rejected whatever their credentials.
+func NewAuthInterceptor(redis RedisToken, tokens TokenValidator, apiKeys APIKeyAuthenticator, accounts AccountStatus, config *config.Config) grpc.UnaryServerInterceptor {
+	policies := LoadPolicies()
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -36,7 +46,11 @@ func NewAuthInterceptor(redis RedisToken, config *config.Config) grpc.UnaryServe
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 
-		if !isProtectedMethod(info.FullMethod) {
+		policy, ok := policies[info.FullMethod]
+		if !ok {
+			policy = defaultPolicy
+		}
+		if policy.Public {
 			return handler(ctx, req)
 		}
 
@@ -45,6 +59,21 @@ func NewAuthInterceptor(redis RedisToken, config *config.Config) grpc.UnaryServe
 			return nil, status.Error(codes.Unauthenticated, "metadata not found")
 		}
 
+		if keys := md.Get("x-api-key"); len(keys) > 0 {
+			principal, err := apiKeys.AuthenticateAPIKey(ctx, keys[0])
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid api key")
+			}
+			if err := authorizeAPIKey(policy, principal); err != nil {
+				return nil, err
+			}
+			if err := checkAccountOpen(ctx, accounts, principal.UserID); err != nil {
+				return nil, err
+			}
+			ctx = context.WithValue(ctx, config.UserIDKey, principal.UserID)
+			return handler(ctx, req)
+		}
+
 		authHeader := md.Get("authorization")
 		if len(authHeader) == 0 {
 			return nil, status.Error(codes.Unauthenticated, "authorization header missing")
@@ -55,7 +84,7 @@ func NewAuthInterceptor(redis RedisToken, config *config.Config) grpc.UnaryServe
 			return nil, status.Error(codes.Unauthenticated, "invalid authorization header format")
 		}
 
-		claims, err := utils.ValidateAccessToken(tokenString, config.JWT.AccessSecret)
+		claims, err := tokens.ValidateAccessToken(tokenString)
 		if err != nil {
 			return nil, status.Errorf(codes.Unauthenticated, "validate : invalid token: %v", err)
 		}
@@ -75,9 +104,43 @@ func NewAuthInterceptor(redis RedisToken, config *config.Config) grpc.UnaryServe
 			return nil, status.Error(codes.Unauthenticated, "token mismatch")
 		}
 
-		fmt.Println(claims.UserID)
+		if len(policy.Roles) > 0 && !hasAnyRole(claims.Roles, policy.Roles) {
+			return nil, status.Errorf(codes.PermissionDenied, "requires role %s", strings.Join(policy.Roles, " or "))
+		}
+
+		if err := checkAccountOpen(ctx, accounts, claims.UserID); err != nil {
+			return nil, err
+		}
 
 		ctx = context.WithValue(ctx, config.UserIDKey, claims.UserID)
 		return handler(ctx, req)
 	}
 }
+
+// authorizeAPIKey checks an API key against policy. Keys carry scopes, not
+// roles, so methods that require a role are closed to them even when the
+// policy also names a scope.
+func authorizeAPIKey(policy *pb.AuthPolicy, principal *model.APIKeyPrincipal) error {
+	if policy.Scope == "" || len(policy.Roles) > 0 {
+		return status.Error(codes.PermissionDenied, "method not available to api keys")
+	}
+	if !principal.HasScope(policy.Scope) {
+		return status.Errorf(codes.PermissionDenied, "api key lacks scope %s", policy.Scope)
+	}
+	return nil
+}
+
+func checkAccountOpen(ctx context.Context, accounts AccountStatus, userID int64) error {
+	accountStatus, err := accounts.GetAccountStatus(ctx, userID)
+	if errors.Is(err, service.ErrUserNotFound) {
+		return status.Error(codes.Unauthenticated, "unknown user")
+	}
+	if err != nil {
+		log.Printf("[Auth] load status for user=%d failed: %v", userID, err)
+		return status.Error(codes.Internal, "auth service unavailable")
+	}
+	if accountStatus == model.StatusClosed {
+		return service.ErrAccountClosed
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"project/config"
+	"project/internal/model"
+	"project/internal/repo"
+	"project/internal/service"
+	"project/internal/utils"
+	pb "project/pkg/pb"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// memAPIKeys implements APIKeyAuthenticator over a fixed set of keys.
+type memAPIKeys map[string]*model.APIKeyPrincipal
+
+func (m memAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*model.APIKeyPrincipal, error) {
+	if p, ok := m[key]; ok {
+		return p, nil
+	}
+	return nil, service.ErrAPIKeyNotFound
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	cfg := config.LoadConfig()
+	signer, err := utils.GenerateEd25519Key()
+	require.NoError(t, err)
+	keys, err := utils.NewKeySet("test", "test", signer)
+	require.NoError(t, err)
+	issue := func(userID int64, roles ...string) string {
+		token, err := keys.GenerateAccessToken(userID, roles, time.Hour)
+		require.NoError(t, err)
+		return token
+	}
+
+	store := repo.NewMemoryTransferRepo()
+	for _, u := range []model.User{
+		{ID: 1, Name: "alice", Roles: "user admin"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol", Status: model.StatusClosed},
+		{ID: 4, Name: "dave"},
+	} {
+		require.NoError(t, store.SeedUser(u))
+	}
+	admin, user, closed := issue(1, "user", "admin"), issue(2, "user"), issue(3, "user")
+	loggedOut, stale := issue(4, "user"), issue(1, "user")
+	sessions := repo.NewMemoryTokenStore()
+	for id, token := range map[int64]string{1: admin, 2: user, 3: closed} {
+		require.NoError(t, sessions.SaveToken(context.Background(), id, token, time.Hour))
+	}
+	apiKeys := memAPIKeys{
+		"send": {UserID: 2, Scopes: []string{model.ScopeTransferSend}},
+		"read": {UserID: 2, Scopes: []string{model.ScopeTransferRead}},
+		"gone": {UserID: 3, Scopes: []string{model.ScopeTransferSend}},
+	}
+	intercept := NewAuthInterceptor(sessions, keys, apiKeys, store, cfg)
+
+	const (
+		login     = "/transfer.v1.AuthService/Login"
+		logout    = "/transfer.v1.AuthService/Logout"
+		sendMoney = "/transfer.v1.TransferService/SendMoney"
+		freeze    = "/transfer.v1.AdminService/FreezeAccount"
+	)
+	bearer := func(token string) metadata.MD { return metadata.Pairs("authorization", "Bearer "+token) }
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantUser int64
+		wantCode codes.Code
+		wantErr  error
+	}{
+		{name: "public method", method: login},
+		{name: "no metadata", method: sendMoney, wantCode: codes.Unauthenticated},
+		{name: "no authorization header", method: sendMoney, md: metadata.Pairs("x-request-id", "1"), wantCode: codes.Unauthenticated},
+		{name: "not a bearer token", method: sendMoney, md: metadata.Pairs("authorization", user), wantCode: codes.Unauthenticated},
+		{name: "malformed token", method: sendMoney, md: bearer("not-a-jwt"), wantCode: codes.Unauthenticated},
+		{name: "logged out token", method: sendMoney, md: bearer(loggedOut), wantCode: codes.Unauthenticated},
+		{name: "superseded token", method: sendMoney, md: bearer(stale), wantCode: codes.Unauthenticated},
+		{name: "user", method: sendMoney, md: bearer(user), wantUser: 2},
+		{name: "default policy", method: logout, md: bearer(user), wantUser: 2},
+		{name: "user on admin method", method: freeze, md: bearer(user), wantCode: codes.PermissionDenied},
+		{name: "admin", method: freeze, md: bearer(admin), wantUser: 1},
+		{name: "closed account", method: sendMoney, md: bearer(closed), wantErr: service.ErrAccountClosed},
+		{name: "api key", method: sendMoney, md: metadata.Pairs("x-api-key", "send"), wantUser: 2},
+		{name: "api key without scope", method: sendMoney, md: metadata.Pairs("x-api-key", "read"), wantCode: codes.PermissionDenied},
+		{name: "api key on unscoped method", method: logout, md: metadata.Pairs("x-api-key", "send"), wantCode: codes.PermissionDenied},
+		{name: "api key on admin method", method: freeze, md: metadata.Pairs("x-api-key", "send"), wantCode: codes.PermissionDenied},
+		{name: "unknown api key", method: sendMoney, md: metadata.Pairs("x-api-key", "nope"), wantCode: codes.Unauthenticated},
+		{name: "api key of closed account", method: sendMoney, md: metadata.Pairs("x-api-key", "gone"), wantErr: service.ErrAccountClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			called := false
+			var gotUser int64
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				gotUser, _ = ctx.Value(cfg.UserIDKey).(int64)
+				return "ok", nil
+			}
+
+			resp, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantCode != codes.OK:
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, "ok", resp)
+				require.True(t, called)
+				require.Equal(t, tt.wantUser, gotUser)
+				return
+			}
+			require.False(t, called)
+		})
+	}
+}
+
+func TestAuthorizeAPIKey(t *testing.T) {
+	principal := &model.APIKeyPrincipal{UserID: 2, Scopes: []string{model.ScopeTransferSend}}
+	tests := []struct {
+		name   string
+		policy *pb.AuthPolicy
+		want   codes.Code
+	}{
+		{name: "scoped method", policy: &pb.AuthPolicy{Scope: model.ScopeTransferSend}},
+		{name: "missing scope", policy: &pb.AuthPolicy{Scope: model.ScopeTransferRead}, want: codes.PermissionDenied},
+		{name: "unscoped method", policy: &pb.AuthPolicy{}, want: codes.PermissionDenied},
+		{name: "scoped method that requires a role", policy: &pb.AuthPolicy{Scope: model.ScopeTransferSend, Roles: []string{"admin"}}, want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, status.Code(authorizeAPIKey(tt.policy, principal)))
+		})
+	}
+}
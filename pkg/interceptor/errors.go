@@ -0,0 +1,77 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"project/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the ErrorInfo domain attached to every mapped domain error.
+const ErrorDomain = "transfer.v1"
+
+// NewErrorInterceptor is the single place where errors returned by handlers
+// become gRPC statuses. Domain errors are mapped by kind and carry an
+// ErrorInfo with their reason; errors that already are statuses pass through;
+// anything else is logged and reported as Internal so driver or SQL messages
+// never reach clients.
+func NewErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return nil, toStatus(info.FullMethod, err)
+	}
+}
+
+func toStatus(method string, err error) error {
+	if derr, ok := service.AsError(err); ok {
+		st := status.New(codeForKind(derr.Kind), derr.Message)
+		withInfo, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   derr.Reason,
+			Domain:   ErrorDomain,
+			Metadata: derr.Metadata,
+		})
+		if detailErr != nil {
+			return st.Err()
+		}
+		return withInfo.Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	log.Printf("[gRPC] %s: %v", method, err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func codeForKind(kind service.ErrorKind) codes.Code {
+	switch kind {
+	case service.KindInvalidArgument:
+		return codes.InvalidArgument
+	case service.KindNotFound:
+		return codes.NotFound
+	case service.KindFailedPrecondition:
+		return codes.FailedPrecondition
+	case service.KindPermissionDenied:
+		return codes.PermissionDenied
+	case service.KindUnauthenticated:
+		return codes.Unauthenticated
+	default:
+		return codes.Internal
+	}
+}
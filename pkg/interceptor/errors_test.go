@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"project/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func callWithError(t *testing.T, handlerErr error) *status.Status {
+	t.Helper()
+	info := &grpc.UnaryServerInfo{FullMethod: "/transfer.v1.TransferService/SendMoney"}
+	_, err := NewErrorInterceptor()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, handlerErr
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	return st
+}
+
+func TestErrorInterceptor_MapsDomainErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{fmt.Errorf("transfer: %w", service.ErrInsufficientFunds), codes.FailedPrecondition, "INSUFFICIENT_FUNDS"},
+		{service.UserNotFound(7), codes.NotFound, "USER_NOT_FOUND"},
+		{service.ErrSelfTransfer, codes.InvalidArgument, "SELF_TRANSFER"},
+		{service.ErrInvalidCredentials, codes.Unauthenticated, "INVALID_CREDENTIALS"},
+		{service.ErrInternal, codes.Internal, "INTERNAL"},
+	}
+	for _, tc := range cases {
+		st := callWithError(t, tc.err)
+		require.Equal(t, tc.code, st.Code())
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		require.Equal(t, tc.reason, info.Reason)
+		require.Equal(t, ErrorDomain, info.Domain)
+	}
+}
+
+func TestErrorInterceptor_HidesUnknownErrors(t *testing.T) {
+	st := callWithError(t, errors.New("pq: relation \"users\" does not exist"))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Equal(t, "internal server error", st.Message())
+
+	st = callWithError(t, status.Error(codes.Unauthenticated, "token revoked or expired"))
+	require.Equal(t, codes.Unauthenticated, st.Code())
+}
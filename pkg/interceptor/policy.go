@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"fmt"
+
+	pb "project/pkg/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// defaultPolicy applies to methods without an auth_policy option: any
+// authenticated user, no API keys.
+var defaultPolicy = &pb.AuthPolicy{}
+
+// LoadPolicies collects the auth_policy option of every method in the
+// registered proto files, keyed by full gRPC method name
+// ("/transfer.v1.AuthService/Login").
+func LoadPolicies() map[string]*pb.AuthPolicy {
+	policies := make(map[string]*pb.AuthPolicy)
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.Get(j)
+				opts := m.Options()
+				if opts == nil || !proto.HasExtension(opts, pb.E_AuthPolicy) {
+					continue
+				}
+				policy := proto.GetExtension(opts, pb.E_AuthPolicy).(*pb.AuthPolicy)
+				policies[fmt.Sprintf("/%s/%s", m.Parent().FullName(), m.Name())] = policy
+			}
+		}
+		return true
+	})
+	return policies
+}
+
+func hasAnyRole(have, want []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package interceptor
+
+import (
+	"testing"
+
+	"project/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	policies := LoadPolicies()
+
+	require.True(t, policies["/transfer.v1.AuthService/Login"].GetPublic())
+	require.Equal(t, model.ScopeTransferSend, policies["/transfer.v1.TransferService/SendMoney"].GetScope())
+	require.Equal(t, model.ScopeTransferSend, policies["/transfer.v1.TransferService/SendMoneyBatch"].GetScope())
+	require.Equal(t, model.ScopeTransferRead, policies["/transfer.v1.TransferService/GetBalance"].GetScope())
+
+	for _, method := range []string{"ListUsers", "GetUser", "ListUserTransactions", "AdjustBalance", "FreezeAccount", "UnfreezeAccount", "ForceLogout"} {
+		require.Equal(t, []string{model.RoleAdmin}, policies["/transfer.v1.AdminService/"+method].GetRoles(), method)
+	}
+
+	// Unannotated methods fall back to the default policy.
+	_, ok := policies["/transfer.v1.AuthService/Logout"]
+	require.False(t, ok)
+}
+
+func TestHasAnyRole(t *testing.T) {
+	require.True(t, hasAnyRole([]string{"user", "admin"}, []string{"admin"}))
+	require.False(t, hasAnyRole([]string{"user"}, []string{"admin", "ops"}))
+	require.False(t, hasAnyRole(nil, []string{"admin"}))
+}
@@ -0,0 +1,96 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"project/internal/model"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// NewRequestInfoInterceptor attaches the caller's IP, user agent and request
+// ID to the context for audit events.
+//
+// The IP is the peer address, unless the peer is one of trustedProxies (CIDRs
+// or single IPs): then it is the last x-forwarded-for entry, the one the
+// gateway appended from the connection it accepted. Earlier entries come from
+// the client, and x-forwarded-for from any other peer is ignored, so a direct
+// gRPC caller cannot choose the IP written to the audit log.
+func NewRequestInfoInterceptor(trustedProxies []string) (grpc.UnaryServerInterceptor, error) {
+	trusted, err := parsePrefixes(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(model.WithRequestInfo(ctx, requestInfo(ctx, trusted)), req)
+	}, nil
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, v := range values {
+		if p, err := netip.ParsePrefix(v); err == nil {
+			out = append(out, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not an IP or CIDR", v)
+		}
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return out, nil
+}
+
+func requestInfo(ctx context.Context, trusted []netip.Prefix) model.RequestInfo {
+	var info model.RequestInfo
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(info.IP); err == nil {
+			info.IP = host
+		}
+	}
+	if xff := md.Get("x-forwarded-for"); len(xff) > 0 && isTrusted(info.IP, trusted) {
+		hops := strings.Split(xff[len(xff)-1], ",")
+		if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+			info.IP = ip
+		}
+	}
+
+	if ua := md.Get("x-forwarded-user-agent"); len(ua) > 0 {
+		info.UserAgent = ua[0]
+	} else if ua := md.Get("user-agent"); len(ua) > 0 {
+		info.UserAgent = ua[0]
+	}
+	if id := md.Get("x-request-id"); len(id) > 0 {
+		info.RequestID = id[0]
+	}
+	return info
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestRequestInfo(t *testing.T) {
+	trusted, err := parsePrefixes([]string{"127.0.0.1/32", "10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+	fromPeer := func(ip string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5000}})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs(
+			"x-forwarded-for", "6.6.6.6, 203.0.113.7",
+			"x-forwarded-user-agent", "curl/8.4",
+			"user-agent", "grpc-go/1.7",
+			"x-request-id", "abc123",
+		))
+	}
+
+	info := requestInfo(peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.9"), Port: 5000}}), trusted)
+	require.Equal(t, "192.0.2.9", info.IP)
+
+	// Behind a trusted gateway the last hop is the client.
+	info = requestInfo(fromPeer("10.1.2.3"), trusted)
+	require.Equal(t, "203.0.113.7", info.IP)
+	require.Equal(t, "curl/8.4", info.UserAgent)
+	require.Equal(t, "abc123", info.RequestID)
+	require.Equal(t, "203.0.113.7", requestInfo(fromPeer("::1"), trusted).IP)
+
+	// A direct caller's x-forwarded-for is ignored.
+	require.Equal(t, "192.0.2.9", requestInfo(fromPeer("192.0.2.9"), trusted).IP)
+	require.Equal(t, "192.0.2.9", requestInfo(fromPeer("192.0.2.9"), nil).IP)
+
+	_, err = parsePrefixes([]string{"gateway"})
+	require.Error(t, err)
+}
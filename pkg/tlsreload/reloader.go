@@ -0,0 +1,222 @@
+// Package tlsreload keeps TLS certificates and CA bundles in sync with files
+// on disk, so rotated certificates (e.g. from a Kubernetes secret) are picked
+// up without a restart.
+package tlsreload
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader holds a key pair and/or a CA pool loaded from files. Either may be
+// empty: a gateway that only verifies the server needs a CA file alone.
+type Reloader struct {
+	certFile, keyFile, caFile string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	version string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("tls: certificate and key files must be set together")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Start polls the files every interval and reloads them when their size or
+// modification time changes. A reload that fails is logged and the previous
+// material stays in use.
+func (r *Reloader) Start(interval time.Duration) {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if err := r.reloadIfChanged(); err != nil {
+					log.Printf("[TLS] reload failed, keeping previous certificates: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *Reloader) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+}
+
+func (r *Reloader) reloadIfChanged() error {
+	v, err := r.fileVersion()
+	if err != nil {
+		return err
+	}
+	r.mu.RLock()
+	same := v == r.version
+	r.mu.RUnlock()
+	if same {
+		return nil
+	}
+	if err := r.reload(); err != nil {
+		return err
+	}
+	log.Printf("[TLS] reloaded %s", r.describe())
+	return nil
+}
+
+func (r *Reloader) reload() error {
+	version, err := r.fileVersion()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("tls: load key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("tls: read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool, r.version = cert, pool, version
+	r.mu.Unlock()
+	return nil
+}
+
+// fileVersion summarises size and mtime of every configured file.
+func (r *Reloader) fileVersion() (string, error) {
+	var v string
+	for _, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f == "" {
+			continue
+		}
+		st, err := os.Stat(f)
+		if err != nil {
+			return "", fmt.Errorf("tls: %w", err)
+		}
+		v += fmt.Sprintf("%s:%d:%d;", f, st.Size(), st.ModTime().UnixNano())
+	}
+	return v, nil
+}
+
+func (r *Reloader) describe() string {
+	if r.caFile == "" {
+		return r.certFile
+	}
+	if r.certFile == "" {
+		return r.caFile
+	}
+	return r.certFile + ", " + r.caFile
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.pool
+}
+
+// ServerConfig serves the current certificate. When a CA file is configured,
+// clients must present a certificate signed by it (mTLS).
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			if cert == nil {
+				return nil, errors.New("tls: no server certificate configured")
+			}
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig verifies the server against the current CA pool (or the system
+// roots when no CA file is set) and presents the current certificate when the
+// server asks for one. Verification is done in VerifyConnection rather than
+// through RootCAs so that a rotated CA applies to new connections.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			if cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("tls: server presented no certificate")
+			}
+			// Go omits SNI for IP addresses, so an explicit name wins.
+			name := serverName
+			if name == "" {
+				name = cs.ServerName
+			}
+			if name == "" {
+				return errors.New("tls: a server name is required to verify the server certificate")
+			}
+			_, pool := r.current()
+			opts := x509.VerifyOptions{
+				Roots:         pool,
+				DNSName:       name,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, c := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
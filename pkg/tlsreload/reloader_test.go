@@ -0,0 +1,161 @@
+package tlsreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a leaf certificate signed by the CA and returns the cert and
+// key file paths.
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// handshake runs a TLS handshake over loopback TCP and returns the server
+// certificate the client saw, or the first error from either side.
+func handshake(t *testing.T, server, client *tls.Config) (*x509.Certificate, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		errc <- tls.Server(conn, server).Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	cli := tls.Client(conn, client)
+	clientErr := cli.Handshake()
+	if clientErr != nil {
+		conn.Close()
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return cli.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestReloader_MutualTLSAndReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+
+	serverCert, serverKey := ca.issue(t, dir, "server", 10)
+	clientCert, clientKey := ca.issue(t, dir, "gateway", 20)
+
+	server, err := New(serverCert, serverKey, caFile)
+	require.NoError(t, err)
+	client, err := New(clientCert, clientKey, caFile)
+	require.NoError(t, err)
+	noClientCert, err := New("", "", caFile)
+	require.NoError(t, err)
+
+	peer, err := handshake(t, server.ServerConfig(), client.ClientConfig("server"))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), peer.SerialNumber.Int64())
+
+	_, err = handshake(t, server.ServerConfig(), noClientCert.ClientConfig("server"))
+	require.Error(t, err, "server must require a client certificate")
+
+	_, err = handshake(t, server.ServerConfig(), client.ClientConfig("other-host"))
+	require.Error(t, err, "client must verify the server name")
+
+	// Rotate the server certificate in place; the next handshake sees it.
+	time.Sleep(10 * time.Millisecond)
+	ca.issue(t, dir, "server", 11)
+	require.NoError(t, server.reloadIfChanged())
+
+	peer, err = handshake(t, server.ServerConfig(), client.ClientConfig("server"))
+	require.NoError(t, err)
+	require.Equal(t, int64(11), peer.SerialNumber.Int64())
+}
+
+func TestReloader_KeepsPreviousOnBadFile(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, dir, "server", 10)
+
+	r, err := New(certFile, keyFile, "")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, []byte("not a certificate"), 0o600))
+	require.Error(t, r.reloadIfChanged())
+
+	cert, _ := r.current()
+	require.NotNil(t, cert)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, int64(10), leaf.SerialNumber.Int64())
+}
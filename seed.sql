@@ -0,0 +1,11 @@
+-- Demo data for local development. Run after `migrate up`; safe to re-run.
+
+INSERT INTO users (id, name, balance, password)
+VALUES
+    (1, 'alice', 10000, '$2a$10$6Uo9VmU.2UTwJ6iXf/3E5eL3zQiQiyN6VvT54StM2Q/Dye09zyGrW'),
+    (2, 'bob', 5000, '$2a$10$0Bch0Vk5AasCtDE1kYrmg.2K3b0DGfxMFjGxa4WcO77eExKcGtB3O'),
+    (3, 'bb', 5000, '$2a$10$QOIEuaIEl2KAd0Jfb3iJVeQIUjoFnYPKFA0JLS3cE.pOwUEOLfv9a')
+ON CONFLICT (id) DO NOTHING;
+
+-- Explicit IDs do not advance the sequence.
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST((SELECT MAX(id) FROM users), 1));